@@ -19,7 +19,10 @@ package commands
 import (
     "errors"
     "fmt"
+    "io/ioutil"
+    "path/filepath"
     "reflect"
+    "strconv"
     "strings"
 
     "../../go-whisk/whisk"
@@ -28,6 +31,7 @@ import (
     "github.com/fatih/color"
     "github.com/spf13/cobra"
     "encoding/json"
+    "gopkg.in/yaml.v2"
 )
 
 //////////////
@@ -47,17 +51,17 @@ var apiCreateCmd = &cobra.Command{
     PreRunE:       setupClientConfig,
     RunE: func(cmd *cobra.Command, args []string) error {
 
-        var api *whisk.Api
+        var apis []*whisk.Api
         var err error
 
-        if (len(args) == 0 && flags.api.configfile == "") {
-            whisk.Debug(whisk.DbgError, "No swagger file and no arguments\n")
-            errMsg := wski18n.T("Invalid argument(s). Specify a swagger file or specify an API path, an API verb, and an action name.") // FIXME MWD add pii
+        if (len(args) == 0 && flags.api.configfile == "" && flags.api.configdir == "") {
+            whisk.Debug(whisk.DbgError, "No swagger file/directory and no arguments\n")
+            errMsg := wski18n.T("Invalid argument(s). Specify a swagger file, a directory of swagger fragments, or specify an API path, an API verb, and an action name.") // FIXME MWD add pii
             whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
                 whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
             return whiskErr
-        } else if (len(args) == 0 && flags.api.configfile != "") {
-            api, err = parseSwaggerApi()
+        } else if (len(args) == 0 && (flags.api.configfile != "" || flags.api.configdir != "")) {
+            apis, err = parseSwaggerApi()
             if err != nil {
                 whisk.Debug(whisk.DbgError, "parseSwaggerApi() error: %s\n", err)
                 errMsg := fmt.Sprintf(
@@ -72,7 +76,7 @@ var apiCreateCmd = &cobra.Command{
                 wski18n.T("An API base path is optional.  An API path, API verb, and action name are required.")); whiskErr != nil {  // FIXME PII
                 return whiskErr
             }
-            api, err = parseApi(cmd, args)
+            api, err := parseApi(cmd, args)
             if err != nil {
                 whisk.Debug(whisk.DbgError, "parseApi(%s, %s) error: %s\n", cmd, args, err)
                 errMsg := fmt.Sprintf(
@@ -82,57 +86,77 @@ var apiCreateCmd = &cobra.Command{
                     whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
                 return whiskErr
             }
+            apis = []*whisk.Api{api}
         }
 
-        sendApi := new(whisk.SendApi)
-        sendApi.ApiDoc = api
+        // Bulk creation (--config-dir, one API per fragment file)
+        // reports per-API success/failure at the end rather than aborting on
+        // the first error, so one bad fragment doesn't block the rest.
+        var failures []string
+        for _, api := range apis {
+            if err := createOneApi(api); err != nil {
+                failures = append(failures, err.Error())
+            }
+        }
 
-        retApi, _, err := client.Apis.Insert(sendApi, false)
-        if err != nil {
-            whisk.Debug(whisk.DbgError, "client.Apis.Insert(%#v, false) error: %s\n", api, err)
-            errMsg := fmt.Sprintf(
-                wski18n.T("Unable to create api: {{.err}}",
-                    map[string]interface{}{"err": err}))
-            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_NETWORK,
+        if (len(failures) > 0) {
+            return whisk.MakeWskError(errors.New(strings.Join(failures, "\n")), whisk.EXITCODE_ERR_GENERAL,
                 whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
-            return whiskErr
         }
+        return nil
+    },
+}
 
-        if (api.Swagger == "") {
-            baseUrl := retApi.Response.Result.BaseUrl
-            fmt.Fprintf(color.Output,
-                wski18n.T("{{.ok}} created api {{.path}} {{.verb}} for action {{.name}}\n{{.fullpath}}\n",
-                    map[string]interface{}{
-                        "ok": color.GreenString("ok:"),
-                        "path": api.GatewayRelPath,
-                        "verb": api.GatewayMethod,
-                        "name": boldString(api.Action.Name),
-                        "fullpath": baseUrl+api.GatewayRelPath,
-                    }))
-        } else {
-            whisk.Debug(whisk.DbgInfo, "Processing swagger based create API response\n")
-            baseUrl := retApi.Response.Result.BaseUrl
-            for path, _ := range retApi.Response.Result.Swagger.Paths {
-                managedUrl := baseUrl+path
-                whisk.Debug(whisk.DbgInfo, "Managed path: %s\n",managedUrl)
-                for op, _  := range retApi.Response.Result.Swagger.Paths[path] {
-                    whisk.Debug(whisk.DbgInfo, "Path operation: %s\n", op)
-                    fmt.Fprintf(color.Output,
-                        wski18n.T("{{.ok}} created api {{.path}} {{.verb}} for action {{.name}}\n{{.fullpath}}\n",
-                            map[string]interface{}{
-                                "ok": color.GreenString("ok:"),
-                                "path": path,
-                                "verb": op,
-                                "name": boldString(retApi.Response.Result.Swagger.Paths[path][op]["x-ibm-op-ext"]["actionName"]),
-                                "fullpath": managedUrl,
-                            }))
-                }
+// createOneApi inserts a single API (direct args or one swagger fragment) and
+// prints the same "created" summary apiCreateCmd has always printed, whether
+// creating one API from CLI args or many from --config-dir/--config-file.
+func createOneApi(api *whisk.Api) error {
+    sendApi := new(whisk.SendApi)
+    sendApi.ApiDoc = api
+
+    retApi, _, err := client.Apis.Insert(sendApi, false)
+    if err != nil {
+        whisk.Debug(whisk.DbgError, "client.Apis.Insert(%#v, false) error: %s\n", api, err)
+        errMsg := fmt.Sprintf(
+            wski18n.T("Unable to create api: {{.err}}",
+                map[string]interface{}{"err": err}))
+        return whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_NETWORK,
+            whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+    }
+
+    if (api.Swagger == "") {
+        baseUrl := retApi.Response.Result.BaseUrl
+        fmt.Fprintf(color.Output,
+            wski18n.T("{{.ok}} created api {{.path}} {{.verb}} for action {{.name}}\n{{.fullpath}}\n",
+                map[string]interface{}{
+                    "ok": color.GreenString("ok:"),
+                    "path": api.GatewayRelPath,
+                    "verb": api.GatewayMethod,
+                    "name": boldString(api.Action.Name),
+                    "fullpath": baseUrl+api.GatewayRelPath,
+                }))
+    } else {
+        whisk.Debug(whisk.DbgInfo, "Processing swagger based create API response\n")
+        baseUrl := retApi.Response.Result.BaseUrl
+        for path, _ := range retApi.Response.Result.Swagger.Paths {
+            managedUrl := baseUrl+path
+            whisk.Debug(whisk.DbgInfo, "Managed path: %s\n",managedUrl)
+            for op, _  := range retApi.Response.Result.Swagger.Paths[path] {
+                whisk.Debug(whisk.DbgInfo, "Path operation: %s\n", op)
+                fmt.Fprintf(color.Output,
+                    wski18n.T("{{.ok}} created api {{.path}} {{.verb}} for action {{.name}}\n{{.fullpath}}\n",
+                        map[string]interface{}{
+                            "ok": color.GreenString("ok:"),
+                            "path": path,
+                            "verb": op,
+                            "name": boldString(retApi.Response.Result.Swagger.Paths[path][op]["x-ibm-op-ext"]["actionName"]),
+                            "fullpath": managedUrl,
+                        }))
             }
         }
+    }
 
-
-        return nil
-    },
+    return nil
 }
 
 var apiUpdateCmd = &cobra.Command{
@@ -158,12 +182,56 @@ var apiUpdateCmd = &cobra.Command{
                 whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
             return whiskErr
         }
+
+        existing, err := getApiSwagger(api.GatewayBasePath)
+        if err != nil {
+            whisk.Debug(whisk.DbgError, "getApiSwagger(%s) error: %s\n", api.GatewayBasePath, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to get existing api: {{.err}}",
+                    map[string]interface{}{"err": err}))
+            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+            return whiskErr
+        }
+
+        before := existingOperation(existing, api.GatewayRelPath, api.GatewayMethod)
+        after := map[string]interface{}{
+            "actionName":      api.Action.Name,
+            "actionNamespace": api.Action.Namespace,
+            "responseType":    api.Action.ResponseType,
+        }
+
+        if (flags.api.dryrun) {
+            printApiUpdatePatch(api.GatewayRelPath, api.GatewayMethod, before, after)
+            return nil
+        }
+
         sendApi := new(whisk.SendApi)
-        sendApi.ApiDoc = api
+        if (flags.api.force) {
+            // Full-replace semantics: submit just this (path, verb), the way
+            // the rest of the base path has always been replaced.
+            sendApi.ApiDoc = api
+        } else {
+            // Merge this (path, verb) into the existing base path's swagger so
+            // sibling routes are left untouched instead of the whole API
+            // momentarily going offline for a delete+recreate.
+            merged, err := mergeApiUpdate(existing, api)
+            if err != nil {
+                whisk.Debug(whisk.DbgError, "mergeApiUpdate(%#v) error: %s\n", api, err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("Unable to merge api update: {{.err}}",
+                        map[string]interface{}{"err": err}))
+                whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+                return whiskErr
+            }
+            api.Swagger = merged
+            sendApi.ApiDoc = api
+        }
 
         retApi, _, err := client.Apis.Insert(sendApi, true)
         if err != nil {
-            whisk.Debug(whisk.DbgError, "client.Apis.Insert(%#v, %t, false) error: %s\n", api, err)
+            whisk.Debug(whisk.DbgError, "client.Apis.Insert(%#v, true) error: %s\n", api, err)
             errMsg := fmt.Sprintf(
                 wski18n.T("Unable to update api: {{.err}}",
                     map[string]interface{}{"err": err}))
@@ -266,6 +334,10 @@ var apiGetCmd = &cobra.Command{
                 whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
             return whiskErr
         }
+
+        if (flags.api.query != "" || isQueryOutputFormat(flags.api.outputformat)) {
+            return printApiQueryResult(displayResult, flags.api.query, flags.api.outputformat)
+        }
         printJSON(displayResult)
 
         return nil
@@ -364,6 +436,186 @@ var apiDeleteCmd = &cobra.Command{
     },
 }
 
+var apiSyncCmd = &cobra.Command{
+    Use:           "sync MANIFEST_FILE",
+    Short:         wski18n.T("reconcile APIs against a declarative manifest"),
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    PreRunE:       setupClientConfig,
+    RunE: func(cmd *cobra.Command, args []string) error {
+
+        if whiskErr := checkArgs(args, 1, 1, "Api sync",
+            wski18n.T("A manifest file is required.")); whiskErr != nil {
+            return whiskErr
+        }
+
+        manifest, err := parseApiManifest(args[0])
+        if err != nil {
+            whisk.Debug(whisk.DbgError, "parseApiManifest(%s) error: %s\n", args[0], err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to parse manifest file '{{.name}}': {{.err}}",
+                    map[string]interface{}{"name": args[0], "err": err}))
+            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+            return whiskErr
+        }
+
+        for _, basePathEntry := range manifest.Apis {
+            plan, err := planApiSync(basePathEntry)
+            if err != nil {
+                return err
+            }
+
+            if (flags.api.dryrun) {
+                printApiSyncPlan(basePathEntry.BasePath, plan)
+                continue
+            }
+
+            if err := applyApiSyncPlan(basePathEntry.BasePath, plan); err != nil {
+                return err
+            }
+
+            fmt.Fprintf(color.Output,
+                wski18n.T("{{.ok}} synced api {{.basepath}} ({{.inserted}} created/updated, {{.deleted}} removed)\n",
+                    map[string]interface{}{
+                        "ok": color.GreenString("ok:"),
+                        "basepath": basePathEntry.BasePath,
+                        "inserted": len(plan.ToInsert),
+                        "deleted": len(plan.ToDelete),
+                    }))
+        }
+
+        return nil
+    },
+}
+
+var apiExportCmd = &cobra.Command{
+    Use:           "export [BASE_PATH]",
+    Short:         wski18n.T("export one or all APIs to a portable swagger/OpenAPI file"),
+    SilenceUsage:  true,
+    SilenceErrors: true,
+    PreRunE:       setupClientConfig,
+    RunE: func(cmd *cobra.Command, args []string) error {
+
+        if whiskErr := checkArgs(args, 0, 1, "Api export",
+            wski18n.T("An optional API base path or API name restricts the export to a single API; omit it to export every API in the namespace.")); whiskErr != nil {
+            return whiskErr
+        }
+
+        var swagger *whisk.ApiSwagger
+        var exportLabel string
+
+        if (len(args) == 0) {
+            apis, err := listAllApisForExport()
+            if err != nil {
+                whisk.Debug(whisk.DbgError, "listAllApisForExport() error: %s\n", err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("Unable to get apis: {{.err}}",
+                        map[string]interface{}{"err": err}))
+                whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+                return whiskErr
+            }
+
+            aggregate, err := aggregateApiSwaggers(apis)
+            if err != nil {
+                whisk.Debug(whisk.DbgError, "aggregateApiSwaggers() error: %s\n", err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("Unable to aggregate apis for export: {{.err}}",
+                        map[string]interface{}{"err": err}))
+                whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+                return whiskErr
+            }
+            swagger = aggregate
+            exportLabel = client.Config.Namespace
+        } else {
+            api := new(whisk.Api)
+            options := new(whisk.ApiListOptions)
+            exportLabel = args[0]
+
+            // Is the argument a basepath (must start with /) or an API name
+            if _, ok := isValidBasepath(args[0]); !ok {
+                whisk.Debug(whisk.DbgInfo, "Treating '%s' as an API name; as it does not begin with '/'\n", args[0])
+                api.ApiName = args[0]
+                api.Id = api.ApiName
+                options.ApiBasePath = args[0]
+                options.ApiName = args[0]
+                api.GatewayBasePath = args[0]
+            } else {
+                api.GatewayBasePath = args[0]
+                options.ApiBasePath = api.GatewayBasePath
+                api.Id = "API:"+api.Namespace+":"+api.GatewayBasePath
+            }
+            api.Namespace = client.Config.Namespace
+
+            retApi, _, err := client.Apis.Get(api, options)
+            if err != nil {
+                whisk.Debug(whisk.DbgError, "client.Apis.Get(%s) error: %s\n", api.Id, err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("Unable to get api: {{.err}}",
+                        map[string]interface{}{"err": err}))
+                whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+                return whiskErr
+            }
+
+            if (retApi.Response != nil && retApi.Response.ResultArray != nil &&
+                retApi.Response.ResultArray.Apis != nil && len(retApi.Response.ResultArray.Apis) > 0 &&
+                retApi.Response.ResultArray.Apis[0].ApiValue != nil) {
+                swagger = retApi.Response.ResultArray.Apis[0].ApiValue.Swagger
+            }
+            if (swagger == nil) {
+                errMsg := fmt.Sprintf(
+                    wski18n.T("API does not exist for basepath {{.basepath}}",   // FIXME PII
+                        map[string]interface{}{"basepath": args[0]}))
+                whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+                return whiskErr
+            }
+        }
+
+        if (!flags.api.keepextensions) {
+            stripOpenWhiskExtensions(swagger)
+        }
+
+        document, err := formatApiExport(swagger, flags.api.format)
+        if err != nil {
+            whisk.Debug(whisk.DbgError, "formatApiExport(%s) error: %s\n", flags.api.format, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to format exported API: {{.err}}",
+                    map[string]interface{}{"err": err}))
+            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+            return whiskErr
+        }
+
+        if (flags.api.output == "") {
+            fmt.Fprintln(color.Output, document)
+            return nil
+        }
+
+        if err := writeFile(flags.api.output, document); err != nil {
+            whisk.Debug(whisk.DbgError, "writeFile(%s) error: %s\n", flags.api.output, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Error writing exported API to '{{.name}}': {{.err}}",
+                    map[string]interface{}{"name": flags.api.output, "err": err}))
+            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+            return whiskErr
+        }
+
+        fmt.Fprintf(color.Output,
+            wski18n.T("{{.ok}} exported api {{.basepath}} to {{.file}}\n",
+                map[string]interface{}{
+                    "ok": color.GreenString("ok:"),
+                    "basepath": exportLabel,
+                    "file": flags.api.output,
+                }))
+        return nil
+    },
+}
+
 var fmtString = "%-30s %7s %20s  %s\n"
 var apiListCmd = &cobra.Command{
     Use:           "list [[BASE_PATH | API_NAME] [API_PATH [API_VERB]]",
@@ -445,6 +697,10 @@ var apiListCmd = &cobra.Command{
             whisk.Debug(whisk.DbgInfo, "client.Apis.Get returned: %#v\n", retApiOrApiArray)
         }
 
+        if (flags.api.query != "" || isQueryOutputFormat(flags.api.outputformat)) {
+            return printApiQueryResult(retApiOrApiArray, flags.api.query, flags.api.outputformat)
+        }
+
         fmt.Fprintf(color.Output,
             wski18n.T("{{.ok}} apis\n",
                 map[string]interface{}{
@@ -471,6 +727,71 @@ var apiListCmd = &cobra.Command{
     },
 }
 
+// isQueryOutputFormat reports whether --output was given a value other than
+// the default table rendering, so callers know to route through
+// printApiQueryResult() instead of their normal table/JSON dump.
+func isQueryOutputFormat(outputFormat string) bool {
+    return outputFormat != "" && outputFormat != "table"
+}
+
+// printApiQueryResult runs `result` (a whisk API response struct) through the
+// --query expression evaluator and prints the selected subtree in the
+// requested --output format ("json" (default), "yaml", or "jsonpath=EXPR"
+// which both selects and forces JSON output).
+func printApiQueryResult(result interface{}, query string, outputFormat string) error {
+    generic, err := toGenericJSON(result)
+    if err != nil {
+        errMsg := fmt.Sprintf(
+            wski18n.T("Unable to format api result: {{.err}}",
+                map[string]interface{}{"err": err}))
+        return whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+    }
+
+    effectiveQuery := query
+    format := outputFormat
+    if (strings.HasPrefix(format, "jsonpath=")) {
+        effectiveQuery = strings.TrimPrefix(format, "jsonpath=")
+        format = "json"
+    }
+
+    selected, err := evalQuery(generic, effectiveQuery)
+    if err != nil {
+        errMsg := fmt.Sprintf(
+            wski18n.T("Invalid --query expression '{{.query}}': {{.err}}",
+                map[string]interface{}{"query": effectiveQuery, "err": err}))
+        whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        return whiskErr
+    }
+
+    if (strings.ToLower(format) == "yaml") {
+        encoded, err := yaml.Marshal(selected)
+        if err != nil {
+            return err
+        }
+        fmt.Fprint(color.Output, string(encoded))
+        return nil
+    }
+
+    printJSON(selected)
+    return nil
+}
+
+// toGenericJSON round-trips a typed whisk API response struct through JSON so
+// it can be walked generically by evalQuery().
+func toGenericJSON(v interface{}) (interface{}, error) {
+    encoded, err := json.Marshal(v)
+    if err != nil {
+        return nil, err
+    }
+    var generic interface{}
+    if err := json.Unmarshal(encoded, &generic); err != nil {
+        return nil, err
+    }
+    return generic, nil
+}
+
 func printListRow(resultApi *whisk.RetApi, api *whisk.Api) {
     baseUrl := resultApi.BaseUrl
     apiName := resultApi.Swagger.Info.Title
@@ -488,7 +809,7 @@ func printListRow(resultApi *whisk.RetApi, api *whisk.Api) {
                             opv["x-ibm-op-ext"]["actionNamespace"].(string)+"/"+opv["x-ibm-op-ext"]["actionName"].(string),
                             op,
                             apiName,
-                            baseUrl+path)
+                            gatewayUrl(baseUrl, path))
                     }
                 }
             }
@@ -582,6 +903,15 @@ func parseApi(cmd *cobra.Command, args []string) (*whisk.Api, error) {
         apiname = flags.api.apiname
     }
 
+    // Is the requested response type valid?  Defaults to "json" when not specified.
+    responseType := flags.api.responsetype
+    if (responseType == "") {
+        responseType = "json"
+    }
+    if whiskErr, ok := IsValidApiResponseType(responseType); !ok {
+        return nil, whiskErr
+    }
+
     api.Namespace = client.Config.Namespace
     api.Action = new(whisk.ApiAction)
     api.Action.BackendUrl = "https://" + client.Config.Host + "/api/v1/namespaces/" + qName.namespace + "/actions/" + qName.entityName
@@ -589,65 +919,664 @@ func parseApi(cmd *cobra.Command, args []string) (*whisk.Api, error) {
     api.Action.Name = qName.entityName
     api.Action.Namespace = qName.namespace
     api.Action.Auth = client.Config.AuthToken
+    api.Action.ResponseType = responseType
     api.ApiName = apiname
     api.GatewayBasePath = basepath
     if (!basepathArgIsApiName) { api.Id = "API:"+api.Namespace+":"+api.GatewayBasePath }
 
-    whisk.Debug(whisk.DbgInfo, "Parsed api struct: %#v\n", api)
-    return api, nil
-}
-
-func parseSwaggerApi() (*whisk.Api, error) {
-    if ( len(flags.api.configfile) == 0 ) {
-        whisk.Debug(whisk.DbgError, "No swagger file is specified\n")
-        errMsg := fmt.Sprintf(
-            wski18n.T("Internal error.  Swagger file is missing."))   // FIXME MWD add to en_us pii
-        whiskErr := whisk.MakeWskError(errors.New(errMsg),whisk.EXITCODE_ERR_GENERAL,
-            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-        return nil, whiskErr
+    // Path parameters (e.g. "/items/{id}") are declared via --path-parameters as
+    // "name:type" pairs; the controller uses these to emit the matching
+    // "parameters: [{in: path, ...}]" swagger entries so the gateway routes them,
+    // and (by default) passes each one through to the action as a top-level input.
+    // This has to run before the CORS and gateway-policy blocks below, since both
+    // synthesize api.Swagger from api.PathParameters and need it already populated.
+    declaredTypes := parsePathParameterFlag(flags.api.pathparameters)
+    for _, name := range pathParameterNames(api.GatewayRelPath) {
+        paramType := declaredTypes[name]
+        if (paramType == "") {
+            paramType = "string"
+        }
+        api.PathParameters = append(api.PathParameters, whisk.ApiPathParameter{
+            Name:        name,
+            Type:        paramType,
+            Passthrough: !flags.api.pathparametersnopassthrough,
+        })
     }
 
-    swagger, err:= readFile(flags.api.configfile)
-    if ( err != nil ) {
-        whisk.Debug(whisk.DbgError, "readFile(%s) error: %s\n", flags.api.configfile, err)
-        errMsg := fmt.Sprintf(
-            wski18n.T("Error reading swagger file '{{.name}}': {{.err}}",
-                map[string]interface{}{"name": flags.api.configfile, "err": err}))   // FIXME MWD add to en_us pii
-        whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
-            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-        return nil, whiskErr
-    }
+    if (flags.api.cors) {
+        api.Cors = buildCorsOptions(flags.api.corsAllowOrigin, flags.api.corsAllowMethods,
+            flags.api.corsAllowHeaders, flags.api.corsMaxAge, flags.api.corsAllowCredentials)
 
-    // Parse the JSON into a swagger object
-    swaggerObj := new(whisk.ApiSwagger)
-    err = json.Unmarshal([]byte(swagger), swaggerObj)
-    if ( err != nil ) {
-        whisk.Debug(whisk.DbgError, "JSON parse of `%s' error: %s\n", flags.api.configfile, err)
-        errMsg := fmt.Sprintf(
-            wski18n.T("Error parsing swagger file '{{.name}}': {{.err}}",
-                map[string]interface{}{"name": flags.api.configfile, "err": err}))   // FIXME MWD add to en_us pii
-        whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
-            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
-        return nil, whiskErr
+        // A preflight OPTIONS operation has to exist for the browser's CORS
+        // preflight request to get a 2xx back, so synthesize a minimal swagger
+        // fragment carrying it alongside the route's normal operation.
+        swagger, err := buildCorsAwareSwagger(api)
+        if err != nil {
+            whisk.Debug(whisk.DbgError, "buildCorsAwareSwagger(%#v) error: %s\n", api, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to generate CORS preflight configuration: {{.err}}",
+                    map[string]interface{}{"err": err}))
+            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+            return nil, whiskErr
+        }
+        api.Swagger = swagger
     }
-    if (swaggerObj.BasePath == "" || swaggerObj.SwaggerName == "" || swaggerObj.Info == nil || swaggerObj.Paths == nil) {
-        whisk.Debug(whisk.DbgError, "Swagger file is invalid.\n", flags.api.configfile, err)
-        errMsg := wski18n.T("Swagger file is invalid (missing basePath, info, paths, or swagger fields")   // FIXME MWD add to en_us pii
+
+    // Gateway policy extensions (rate limiting, API key/JWT enforcement) let
+    // users protect an exposed route without an out-of-band gateway console.
+    if ((flags.api.jwtIssuer == "") != (flags.api.jwtAudience == "")) {
+        errMsg := wski18n.T("--jwt-issuer and --jwt-audience must be specified together.")
         whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
             whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
         return nil, whiskErr
     }
 
-    api := new(whisk.Api)
-    api.Namespace = client.Config.Namespace
-    api.Swagger = swagger
-
-    return api, nil
-}
+    rateLimit, err := parseRateLimitFlag(flags.api.ratelimit)
+    if err != nil {
+        return nil, err
+    }
 
-func IsValidApiVerb(verb string) (error, bool) {
-    // Is the API verb valid?
-    if _, ok := whisk.ApiVerbs[strings.ToUpper(verb)]; !ok {
+    if (rateLimit != nil || flags.api.requireapikey || flags.api.jwtIssuer != "") {
+        swagger, err := applyGatewayPolicies(api, rateLimit, flags.api.requireapikey,
+            flags.api.jwtIssuer, flags.api.jwtAudience)
+        if err != nil {
+            whisk.Debug(whisk.DbgError, "applyGatewayPolicies(%#v) error: %s\n", api, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to apply gateway policies: {{.err}}",
+                    map[string]interface{}{"err": err}))
+            whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+            return nil, whiskErr
+        }
+        api.Swagger = swagger
+    }
+
+    whisk.Debug(whisk.DbgInfo, "Parsed api struct: %#v\n", api)
+    return api, nil
+}
+
+// apiRateLimit is the parsed form of a --rate-limit "N/(sec|min|hour)" flag.
+type apiRateLimit struct {
+    Rate int
+    Unit string
+}
+
+var apiRateLimitUnits = map[string]bool{"sec": true, "min": true, "hour": true}
+
+// parseRateLimitFlag parses a --rate-limit value of the form "N/(sec|min|hour)",
+// e.g. "100/min", returning (nil, nil) when the flag wasn't specified.
+func parseRateLimitFlag(value string) (*apiRateLimit, error) {
+    if (value == "") {
+        return nil, nil
+    }
+
+    invalid := func() (*apiRateLimit, error) {
+        errMsg := fmt.Sprintf(
+            wski18n.T("'{{.value}}' is not a valid --rate-limit; expected N/(sec|min|hour).",
+                map[string]interface{}{"value": value}))
+        return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+    }
+
+    parts := strings.SplitN(value, "/", 2)
+    if (len(parts) != 2 || !apiRateLimitUnits[parts[1]]) {
+        return invalid()
+    }
+    rate, err := strconv.Atoi(parts[0])
+    if (err != nil || rate <= 0) {
+        return invalid()
+    }
+    return &apiRateLimit{Rate: rate, Unit: parts[1]}, nil
+}
+
+// applyGatewayPolicies folds rate-limit and API key/JWT security policy
+// extensions into api's operation within its swagger document, creating a
+// minimal single-operation document first if one doesn't already exist (e.g.
+// because --cors wasn't also specified). These ride along as
+// x-openwhisk-ratelimit/x-openwhisk-security extensions the same way CORS
+// rides along as x-openwhisk-cors, so `wsk api get --full` shows them for
+// free once they're part of the submitted swagger.
+func applyGatewayPolicies(api *whisk.Api, rateLimit *apiRateLimit, requireApiKey bool, jwtIssuer string, jwtAudience string) (string, error) {
+    swagger2 := make(map[string]interface{})
+    if (api.Swagger != "") {
+        if err := json.Unmarshal([]byte(api.Swagger), &swagger2); err != nil {
+            return "", err
+        }
+    } else {
+        swagger2["swagger"] = "2.0"
+        swagger2["info"] = map[string]interface{}{"title": api.GatewayBasePath, "version": "1.0.0"}
+        swagger2["basePath"] = api.GatewayBasePath
+    }
+
+    paths, ok := swagger2["paths"].(map[string]interface{})
+    if (!ok) {
+        paths = make(map[string]interface{})
+    }
+    verbs, ok := paths[api.GatewayRelPath].(map[string]interface{})
+    if (!ok) {
+        verbs = make(map[string]interface{})
+    }
+    verb := strings.ToLower(api.GatewayMethod)
+    operation, ok := verbs[verb].(map[string]interface{})
+    if (!ok) {
+        operation = map[string]interface{}{
+            "x-ibm-op-ext": map[string]interface{}{
+                "actionName":      api.Action.Name,
+                "actionNamespace": api.Action.Namespace,
+                "backendMethod":   api.Action.BackendMethod,
+                "responseType":    api.Action.ResponseType,
+            },
+            "responses": map[string]interface{}{"default": map[string]interface{}{"description": "Default response"}},
+        }
+        if params := pathParameterSwaggerEntries(api); len(params) > 0 {
+            operation["parameters"] = params
+        }
+    }
+
+    if (rateLimit != nil) {
+        operation["x-openwhisk-ratelimit"] = map[string]interface{}{
+            "rate": rateLimit.Rate,
+            "unit": rateLimit.Unit,
+        }
+    }
+    if (requireApiKey || jwtIssuer != "") {
+        security := map[string]interface{}{
+            "requireApiKey": requireApiKey,
+        }
+        if (jwtIssuer != "") {
+            security["jwt"] = map[string]interface{}{
+                "issuer":   jwtIssuer,
+                "audience": jwtAudience,
+            }
+        }
+        operation["x-openwhisk-security"] = security
+    }
+
+    verbs[verb] = operation
+    paths[api.GatewayRelPath] = verbs
+    swagger2["paths"] = paths
+
+    encoded, err := json.Marshal(swagger2)
+    if err != nil {
+        return "", err
+    }
+    return string(encoded), nil
+}
+
+// apiSpecVersion is used to sniff whether a --config-file document is a Swagger
+// 2.0, an OpenAPI 3.x, or an AsyncAPI 2.x document before it is parsed for real.
+type apiSpecVersion struct {
+    Swagger   string                 `json:"swagger"`
+    OpenApi   string                 `json:"openapi"`
+    AsyncApi  string                 `json:"asyncapi"`
+    Info      map[string]interface{} `json:"info"`
+    Paths     map[string]interface{} `json:"paths"`
+    Servers   []map[string]interface{} `json:"servers"`
+}
+
+// validateOpenApi3Document rejects OpenAPI 3 documents that are missing the
+// fields translateOpenApi3ToSwagger2() depends on: a 3.0.x `openapi` version, a
+// non-empty `info`, and either `paths` or a `servers[0].url` to derive the
+// basePath from.
+func validateOpenApi3Document(doc *apiSpecVersion) error {
+    if (!strings.HasPrefix(doc.OpenApi, "3.0")) {
+        return fmt.Errorf("unsupported openapi version '%s'; only 3.0.x is supported", doc.OpenApi)
+    }
+    if (len(doc.Info) == 0) {
+        return fmt.Errorf("missing or empty 'info' section")
+    }
+    hasServerUrl := len(doc.Servers) > 0 && doc.Servers[0]["url"] != nil && doc.Servers[0]["url"] != ""
+    if (len(doc.Paths) == 0 && !hasServerUrl) {
+        return fmt.Errorf("document has neither 'paths' nor a 'servers[0].url' to derive a base path from")
+    }
+    return nil
+}
+
+// parseSwaggerApi resolves --config-file/--config-dir into one or more
+// whisk.Api fragments ready to hand to client.Apis.Insert().
+func parseSwaggerApi() ([]*whisk.Api, error) {
+    if (len(flags.api.configfile) > 0 && len(flags.api.configdir) > 0) {
+        errMsg := wski18n.T("Only one of --config-file or --config-dir may be specified.")  // FIXME pii
+        return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+    }
+
+    if (len(flags.api.configdir) > 0) {
+        return parseSwaggerApiDir(flags.api.configdir)
+    }
+
+    if ( len(flags.api.configfile) == 0 ) {
+        whisk.Debug(whisk.DbgError, "No swagger file is specified\n")
+        errMsg := fmt.Sprintf(
+            wski18n.T("Internal error.  Swagger file is missing."))   // FIXME MWD add to en_us pii
+        whiskErr := whisk.MakeWskError(errors.New(errMsg),whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        return nil, whiskErr
+    }
+
+    api, err := parseSwaggerApiFile(flags.api.configfile)
+    if err != nil {
+        return nil, err
+    }
+    return []*whisk.Api{api}, nil
+}
+
+// parseSwaggerApiDir walks a directory of swagger/OpenAPI fragments (one per
+// resource, the way wskdeploy manages packages), parsing each independently
+// and reporting per-file failures instead of aborting on the first one.
+func parseSwaggerApiDir(configdir string) ([]*whisk.Api, error) {
+    entries, err := ioutil.ReadDir(configdir)
+    if err != nil {
+        whisk.Debug(whisk.DbgError, "ioutil.ReadDir(%s) error: %s\n", configdir, err)
+        errMsg := fmt.Sprintf(
+            wski18n.T("Error reading config directory '{{.name}}': {{.err}}",
+                map[string]interface{}{"name": configdir, "err": err}))
+        return nil, whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+    }
+
+    var apis []*whisk.Api
+    var failures []string
+    seenRoutes := make(map[string]string)
+
+    for _, entry := range entries {
+        if (entry.IsDir()) {
+            continue
+        }
+        fragmentPath := filepath.Join(configdir, entry.Name())
+
+        api, err := parseSwaggerApiFile(fragmentPath)
+        if err != nil {
+            failures = append(failures, fmt.Sprintf("%s: %s", entry.Name(), err))
+            continue
+        }
+
+        if dupErr := checkDuplicateRoutes(api, entry.Name(), seenRoutes); dupErr != nil {
+            failures = append(failures, dupErr.Error())
+            continue
+        }
+
+        apis = append(apis, api)
+    }
+
+    if (len(failures) > 0) {
+        for _, failure := range failures {
+            fmt.Fprintf(color.Output,
+                wski18n.T("{{.x}} {{.failure}}\n",
+                    map[string]interface{}{"x": color.RedString("x:"), "failure": failure}))
+        }
+        if (len(apis) == 0) {
+            errMsg := fmt.Sprintf(
+                wski18n.T("No API fragment in '{{.dir}}' could be parsed.",
+                    map[string]interface{}{"dir": configdir}))
+            return nil, whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+        }
+    }
+
+    return apis, nil
+}
+
+// checkDuplicateRoutes records every (path, verb) pair declared by `api` in
+// `seenRoutes`, failing if one was already declared by an earlier fragment.
+func checkDuplicateRoutes(api *whisk.Api, filename string, seenRoutes map[string]string) error {
+    swaggerObj := new(whisk.ApiSwagger)
+    if err := json.Unmarshal([]byte(api.Swagger), swaggerObj); err != nil || swaggerObj.Paths == nil {
+        return nil   // already validated by parseSwaggerApiFile; nothing further to check here
+    }
+    for path, ops := range swaggerObj.Paths {
+        for verb, _ := range ops {
+            key := path + " " + strings.ToUpper(verb)
+            if existingFile, ok := seenRoutes[key]; ok {
+                return fmt.Errorf("%s: route %s is already defined in %s", filename, key, existingFile)
+            }
+            seenRoutes[key] = filename
+        }
+    }
+    return nil
+}
+
+func parseSwaggerApiFile(configfile string) (*whisk.Api, error) {
+    swagger, err:= readFile(configfile)
+    if ( err != nil ) {
+        whisk.Debug(whisk.DbgError, "readFile(%s) error: %s\n", configfile, err)
+        errMsg := fmt.Sprintf(
+            wski18n.T("Error reading swagger file '{{.name}}': {{.err}}",
+                map[string]interface{}{"name": configfile, "err": err}))   // FIXME MWD add to en_us pii
+        whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        return nil, whiskErr
+    }
+
+    // OpenAPI 3.x documents are downgraded to the Swagger 2.0 shape the rest of
+    // this file (and the controller-bound SendApi payload) already understands,
+    // so everything downstream of this point keeps working unchanged.
+    specVersion := new(apiSpecVersion)
+    if err := json.Unmarshal([]byte(swagger), specVersion); err == nil {
+        if (specVersion.AsyncApi != "") {
+            // AsyncAPI describes message channels, not HTTP routes; there is no
+            // meaningful Swagger 2.0 translation, so fail clearly instead of
+            // silently producing a broken API.
+            whisk.Debug(whisk.DbgError, "Detected AsyncAPI document (asyncapi: %s); not supported\n", specVersion.AsyncApi)
+            errMsg := fmt.Sprintf(
+                wski18n.T("AsyncAPI documents are not supported by 'wsk api create --config-file'; found asyncapi: {{.version}}",
+                    map[string]interface{}{"version": specVersion.AsyncApi}))
+            whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+            return nil, whiskErr
+        }
+
+        if (strings.HasPrefix(specVersion.OpenApi, "3.")) {
+            whisk.Debug(whisk.DbgInfo, "Detected OpenAPI 3.x document (openapi: %s); translating to Swagger 2.0\n", specVersion.OpenApi)
+            if err := validateOpenApi3Document(specVersion); err != nil {
+                whisk.Debug(whisk.DbgError, "validateOpenApi3Document(%s) error: %s\n", configfile, err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("OpenAPI document '{{.name}}' is invalid: {{.err}}",
+                        map[string]interface{}{"name": configfile, "err": err}))   // FIXME MWD add to en_us pii
+                whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+                return nil, whiskErr
+            }
+
+            swagger, err = translateOpenApi3ToSwagger2(swagger)
+            if err != nil {
+                whisk.Debug(whisk.DbgError, "translateOpenApi3ToSwagger2(%s) error: %s\n", configfile, err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("Error translating OpenAPI document '{{.name}}': {{.err}}",
+                        map[string]interface{}{"name": configfile, "err": err}))   // FIXME MWD add to en_us pii
+                whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+                return nil, whiskErr
+            }
+        }
+    }
+
+    // Parse the JSON into a swagger object
+    swaggerObj := new(whisk.ApiSwagger)
+    err = json.Unmarshal([]byte(swagger), swaggerObj)
+    if ( err != nil ) {
+        whisk.Debug(whisk.DbgError, "JSON parse of `%s' error: %s\n", configfile, err)
+        errMsg := fmt.Sprintf(
+            wski18n.T("Error parsing swagger file '{{.name}}': {{.err}}",
+                map[string]interface{}{"name": configfile, "err": err}))   // FIXME MWD add to en_us pii
+        whiskErr := whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        return nil, whiskErr
+    }
+    if (swaggerObj.BasePath == "" || swaggerObj.SwaggerName == "" || swaggerObj.Info == nil || swaggerObj.Paths == nil) {
+        whisk.Debug(whisk.DbgError, "Swagger file is invalid.\n", configfile, err)
+        errMsg := wski18n.T("Swagger file is invalid (missing basePath, info, paths, or swagger fields")   // FIXME MWD add to en_us pii
+        whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        return nil, whiskErr
+    }
+
+    api := new(whisk.Api)
+    api.Namespace = client.Config.Namespace
+    api.Swagger = swagger
+
+    return api, nil
+}
+
+// apiManifest is the YAML shape accepted by `wsk api sync`: one or more base
+// paths, each declaring the path/verb/action mappings that should exist.
+type apiManifest struct {
+    Apis []apiManifestBasePath `yaml:"apis"`
+}
+
+type apiManifestBasePath struct {
+    BasePath string                                 `yaml:"basePath"`
+    Paths    map[string]map[string]apiManifestRoute `yaml:"paths"`
+}
+
+type apiManifestRoute struct {
+    Action       string `yaml:"action"`
+    ResponseType string `yaml:"responseType"`
+    Cors         bool   `yaml:"cors"`
+}
+
+// apiSyncRoute identifies a single (path, verb) pair within a base path, along
+// with the action it should be bound to when inserting.
+type apiSyncRoute struct {
+    Path         string
+    Verb         string
+    Action       string
+    ResponseType string
+    Cors         bool
+}
+
+// apiSyncPlan is the minimum set of Insert/Delete calls needed to converge a
+// base path's current state on the controller with its manifest declaration.
+type apiSyncPlan struct {
+    ToInsert []apiSyncRoute
+    ToDelete []apiSyncRoute
+}
+
+func parseApiManifest(manifestFile string) (*apiManifest, error) {
+    contents, err := readFile(manifestFile)
+    if err != nil {
+        return nil, err
+    }
+
+    manifest := new(apiManifest)
+    if err := yaml.Unmarshal([]byte(contents), manifest); err != nil {
+        return nil, err
+    }
+    return manifest, nil
+}
+
+// planApiSync fetches the current swagger for a base path and diffs it against
+// the manifest-declared paths, returning the routes that need to be inserted
+// (created or changed) and the routes that are no longer declared and should
+// be deleted.
+func planApiSync(entry apiManifestBasePath) (*apiSyncPlan, error) {
+    api := new(whisk.Api)
+    api.GatewayBasePath = entry.BasePath
+    api.Namespace = client.Config.Namespace
+    api.Id = "API:"+api.Namespace+":"+api.GatewayBasePath
+
+    options := new(whisk.ApiListOptions)
+    options.ApiBasePath = entry.BasePath
+
+    existing := make(map[string]map[string]bool)
+    existingOps := make(map[string]map[string]map[string]interface{})
+    retApi, _, err := client.Apis.Get(api, options)
+    if err != nil {
+        whisk.Debug(whisk.DbgError, "client.Apis.Get(%s) error: %s\n", api.Id, err)
+        errMsg := fmt.Sprintf(
+            wski18n.T("Unable to get api: {{.err}}",
+                map[string]interface{}{"err": err}))
+        return nil, whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+    }
+    if (retApi.Response != nil && retApi.Response.ResultArray != nil && retApi.Response.ResultArray.Apis != nil) {
+        for _, existingApi := range retApi.Response.ResultArray.Apis {
+            if (existingApi.ApiValue == nil || existingApi.ApiValue.Swagger == nil) {
+                continue
+            }
+            for path, ops := range existingApi.ApiValue.Swagger.Paths {
+                verbs := make(map[string]bool)
+                opsByVerb := make(map[string]map[string]interface{})
+                for verb, op := range ops {
+                    verbs[strings.ToUpper(verb)] = true
+                    opsByVerb[strings.ToUpper(verb)] = op
+                }
+                existing[path] = verbs
+                existingOps[path] = opsByVerb
+            }
+        }
+    }
+
+    return computeApiSyncPlan(entry.Paths, existing, existingOps), nil
+}
+
+// computeApiSyncPlan is the pure diffing half of planApiSync: given the
+// manifest-declared paths for a base path and the routes/operations currently
+// registered on the controller (as planApiSync collects them from
+// client.Apis.Get), it returns the minimal Insert/Delete set. Split out from
+// planApiSync so the diff logic can be unit-tested without a live client.
+func computeApiSyncPlan(entryPaths map[string]map[string]apiManifestRoute, existing map[string]map[string]bool, existingOps map[string]map[string]map[string]interface{}) *apiSyncPlan {
+    plan := new(apiSyncPlan)
+    for path, verbs := range entryPaths {
+        for verb, route := range verbs {
+            verbUpper := strings.ToUpper(verb)
+            if (existing[path] != nil) {
+                delete(existing[path], verbUpper)
+                // A cors route's OPTIONS preflight is declared by that route,
+                // so it must not be queued for deletion below.
+                if (route.Cors) {
+                    delete(existing[path], "OPTIONS")
+                }
+            }
+            _, hasOptions := existingOps[path]["OPTIONS"]
+            if (!apiSyncRouteUpToDate(route, existingOps[path][verbUpper], hasOptions)) {
+                plan.ToInsert = append(plan.ToInsert, apiSyncRoute{
+                    Path: path, Verb: verbUpper, Action: route.Action,
+                    ResponseType: route.ResponseType, Cors: route.Cors,
+                })
+            }
+        }
+    }
+    // Whatever is left in `existing` is no longer declared by the manifest.
+    for path, verbs := range existing {
+        for verb, _ := range verbs {
+            plan.ToDelete = append(plan.ToDelete, apiSyncRoute{Path: path, Verb: verb})
+        }
+    }
+
+    return plan
+}
+
+// apiSyncRouteUpToDate reports whether a manifest-declared route already
+// matches the operation currently registered on the controller, so
+// planApiSync() only inserts a route when its action, response type, or CORS
+// setting actually changed (or it doesn't exist yet).
+func apiSyncRouteUpToDate(route apiManifestRoute, existingOp map[string]interface{}, existingHasOptions bool) bool {
+    if (existingOp == nil) {
+        return false
+    }
+    ext, ok := existingOp["x-ibm-op-ext"].(map[string]interface{})
+    if (!ok) {
+        return false
+    }
+
+    qName, err := parseQualifiedName(route.Action)
+    if (err != nil) {
+        return false
+    }
+    if (ext["actionName"] != qName.entityName || ext["actionNamespace"] != qName.namespace) {
+        return false
+    }
+
+    wantResponseType := route.ResponseType
+    if (wantResponseType == "") {
+        wantResponseType = "json"
+    }
+    if (ext["responseType"] != wantResponseType) {
+        return false
+    }
+
+    return route.Cors == existingHasOptions
+}
+
+func printApiSyncPlan(basePath string, plan *apiSyncPlan) {
+    fmt.Fprintf(color.Output,
+        wski18n.T("{{.ok}} sync plan for {{.basepath}}\n",
+            map[string]interface{}{"ok": color.GreenString("plan:"), "basepath": basePath}))
+    for _, route := range plan.ToInsert {
+        fmt.Printf("  + %s %s -> %s\n", route.Verb, route.Path, route.Action)
+    }
+    for _, route := range plan.ToDelete {
+        fmt.Printf("  - %s %s\n", route.Verb, route.Path)
+    }
+}
+
+func applyApiSyncPlan(basePath string, plan *apiSyncPlan) error {
+    for _, route := range plan.ToInsert {
+        qName, err := parseQualifiedName(route.Action)
+        if err != nil {
+            whisk.Debug(whisk.DbgError, "parseQualifiedName(%s) failed: %s\n", route.Action, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("'{{.name}}' is not a valid action name: {{.err}}",
+                    map[string]interface{}{"name": route.Action, "err": err}))
+            return whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        }
+
+        api := new(whisk.Api)
+        api.Namespace = client.Config.Namespace
+        api.GatewayBasePath = basePath
+        api.GatewayRelPath = route.Path
+        api.GatewayMethod = route.Verb
+        api.Id = "API:"+api.Namespace+":"+api.GatewayBasePath
+        api.Action = new(whisk.ApiAction)
+        api.Action.BackendUrl = "https://" + client.Config.Host + "/api/v1/namespaces/" + qName.namespace + "/actions/" + qName.entityName
+        api.Action.BackendMethod = "POST"
+        api.Action.Name = qName.entityName
+        api.Action.Namespace = qName.namespace
+        api.Action.Auth = client.Config.AuthToken
+        api.Action.ResponseType = route.ResponseType
+        if (api.Action.ResponseType == "") {
+            api.Action.ResponseType = "json"
+        }
+
+        if (route.Cors) {
+            api.Cors = buildCorsOptions("", "", "", 0, false)
+            swagger, err := buildCorsAwareSwagger(api)
+            if err != nil {
+                whisk.Debug(whisk.DbgError, "buildCorsAwareSwagger(%#v) error: %s\n", api, err)
+                errMsg := fmt.Sprintf(
+                    wski18n.T("Unable to generate CORS preflight configuration: {{.err}}",
+                        map[string]interface{}{"err": err}))
+                return whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                    whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+            }
+            api.Swagger = swagger
+        }
+
+        sendApi := new(whisk.SendApi)
+        sendApi.ApiDoc = api
+        if _, _, err := client.Apis.Insert(sendApi, true); err != nil {
+            whisk.Debug(whisk.DbgError, "client.Apis.Insert(%#v, true) error: %s\n", api, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to create api: {{.err}}",
+                    map[string]interface{}{"err": err}))
+            return whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_NETWORK,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+        }
+    }
+
+    for _, route := range plan.ToDelete {
+        api := new(whisk.Api)
+        api.Namespace = client.Config.Namespace
+        api.GatewayBasePath = basePath
+        api.GatewayRelPath = route.Path
+        api.GatewayMethod = route.Verb
+        api.Id = "API:"+api.Namespace+":"+api.GatewayBasePath
+
+        options := new(whisk.ApiOptions)
+        options.ApiBasePath = basePath
+        options.ApiRelPath = route.Path
+        options.ApiVerb = route.Verb
+        options.Force = true
+
+        if _, err := client.Apis.Delete(api, options); err != nil {
+            whisk.Debug(whisk.DbgError, "client.Apis.Delete(%s) error: %s\n", api.Id, err)
+            errMsg := fmt.Sprintf(
+                wski18n.T("Unable to delete api: {{.err}}",
+                    map[string]interface{}{"err": err}))
+            return whisk.MakeWskErrorFromWskError(errors.New(errMsg), err, whisk.EXITCODE_ERR_GENERAL,
+                whisk.DISPLAY_MSG, whisk.NO_DISPLAY_USAGE)
+        }
+    }
+
+    return nil
+}
+
+func IsValidApiVerb(verb string) (error, bool) {
+    // Is the API verb valid?
+    if _, ok := whisk.ApiVerbs[strings.ToUpper(verb)]; !ok {
         whisk.Debug(whisk.DbgError, "Invalid API verb: %s\n", verb)
         errMsg := fmt.Sprintf(
             wski18n.T("'{{.verb}}' is not a valid API verb.  Valid values are: {{.verbs}}",
@@ -661,6 +1590,31 @@ func IsValidApiVerb(verb string) (error, bool) {
     return nil, true
 }
 
+// apiResponseTypes enumerates the gateway response types a route's backing
+// action can be marshalled as, mirroring whisk.ApiVerbs in IsValidApiVerb above.
+var apiResponseTypes = map[string]bool{
+    "json": true,
+    "http": true,
+    "text": true,
+    "html": true,
+    "svg":  true,
+}
+
+func IsValidApiResponseType(responseType string) (error, bool) {
+    if _, ok := apiResponseTypes[strings.ToLower(responseType)]; !ok {
+        whisk.Debug(whisk.DbgError, "Invalid API response type: %s\n", responseType)
+        errMsg := fmt.Sprintf(
+            wski18n.T("'{{.type}}' is not a valid API response type.  Valid values are: {{.types}}",
+                map[string]interface{}{
+                    "type": responseType,
+                    "types": reflect.ValueOf(apiResponseTypes).MapKeys()}))
+        whiskErr := whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+            whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+        return whiskErr, false
+    }
+    return nil, true
+}
+
 func hasPathPrefix(path string) (error, bool) {
     if (! strings.HasPrefix(path, "/")) {
         whisk.Debug(whisk.DbgError, "path does not begin with '/': %s\n", path)
@@ -687,9 +1641,88 @@ func isValidRelpath(relpath string) (error, bool) {
     if whiskerr, ok := hasPathPrefix(relpath); !ok {
         return whiskerr, false
     }
+    if whiskerr, ok := hasBalancedPathParameters(relpath); !ok {
+        return whiskerr, false
+    }
     return nil, true
 }
 
+// hasBalancedPathParameters rejects relative paths with malformed `{name}`
+// path-parameter segments (e.g. unbalanced or empty braces), while allowing
+// well-formed ones like "/items/{id}".
+func hasBalancedPathParameters(path string) (error, bool) {
+    depth := 0
+    nameStart := 0
+    for i, c := range path {
+        switch c {
+        case '{':
+            if (depth > 0) {
+                return invalidPathParameterError(path), false
+            }
+            depth++
+            nameStart = i + 1
+        case '}':
+            depth--
+            if (depth != 0 || i == nameStart) {
+                return invalidPathParameterError(path), false
+            }
+        }
+    }
+    if (depth != 0) {
+        return invalidPathParameterError(path), false
+    }
+    return nil, true
+}
+
+func invalidPathParameterError(path string) error {
+    errMsg := fmt.Sprintf(
+        wski18n.T("'{{.path}}' has a malformed path parameter; use the form '{name}'.",
+            map[string]interface{}{"path": path}))
+    return whisk.MakeWskError(errors.New(errMsg), whisk.EXITCODE_ERR_GENERAL,
+        whisk.DISPLAY_MSG, whisk.DISPLAY_USAGE)
+}
+
+// pathParameterNames extracts the `{name}` segments from a relative path, in
+// the order they appear.
+func pathParameterNames(path string) []string {
+    var names []string
+    inParam := false
+    start := 0
+    for i, c := range path {
+        switch c {
+        case '{':
+            inParam = true
+            start = i + 1
+        case '}':
+            if (inParam) {
+                names = append(names, path[start:i])
+                inParam = false
+            }
+        }
+    }
+    return names
+}
+
+// parsePathParameterFlag parses the --path-parameters flag value, a comma
+// separated list of "name:type" pairs (type is one of string, integer, number;
+// string is assumed when omitted), into a name->type map.
+func parsePathParameterFlag(flagValue string) map[string]string {
+    types := make(map[string]string)
+    if (flagValue == "") {
+        return types
+    }
+    for _, pair := range strings.Split(flagValue, ",") {
+        parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+        name := parts[0]
+        paramType := "string"
+        if (len(parts) == 2 && parts[1] != "") {
+            paramType = parts[1]
+        }
+        types[name] = paramType
+    }
+    return types
+}
+
 /*
  * Pull the managedUrl (external API URL) from the API configuration
  */
@@ -704,13 +1737,529 @@ func getManagedUrl(api *whisk.RetApi, relpath string, operation string) (url str
                 whisk.Debug(whisk.DbgInfo, "getManagedUrl: comparing operation: '%s'\n", op)
                 if (strings.ToLower(op) == strings.ToLower(operation)) {
                     whisk.Debug(whisk.DbgInfo, "getManagedUrl: operation matches: %s\n", operation)
-                    url = baseUrl+path
+                    url = gatewayUrl(baseUrl, path)
+                }
+            }
+        }
+    }
+    return url
+}
+
+// gatewayUrl joins a base URL and a gateway relative path, collapsing the
+// duplicate path delimiter that can occur when the basepath ends with '/'.
+// Path-parameter segments (e.g. "{id}") are rendered verbatim rather than
+// percent-escaped, since they are literal placeholders, not literal path data.
+func gatewayUrl(baseUrl string, path string) string {
+    return strings.Replace(baseUrl+path, "//", "/", -1)
+}
+
+// pathParameterSwaggerEntries renders api.PathParameters as the swagger
+// "parameters: [{in: path, ...}]" entries the gateway needs in order to
+// actually route them, per the --path-parameters flag handled in parseApi().
+// Returns nil when api has no path parameters.
+func pathParameterSwaggerEntries(api *whisk.Api) []interface{} {
+    if (len(api.PathParameters) == 0) {
+        return nil
+    }
+    var params []interface{}
+    for _, param := range api.PathParameters {
+        params = append(params, map[string]interface{}{
+            "name":     param.Name,
+            "in":       "path",
+            "required": true,
+            "type":     param.Type,
+        })
+    }
+    return params
+}
+
+// buildCorsOptions fills in a whisk.ApiCorsOptions from the --cors-* flag
+// values, applying the same permissive defaults a hand-written swagger CORS
+// config would typically use.
+func buildCorsOptions(allowOrigin string, allowMethods string, allowHeaders string, maxAge int, allowCredentials bool) *whisk.ApiCorsOptions {
+    cors := new(whisk.ApiCorsOptions)
+    cors.Enabled = true
+    cors.AllowOrigin = allowOrigin
+    cors.AllowMethods = allowMethods
+    cors.AllowHeaders = allowHeaders
+    cors.MaxAge = maxAge
+    cors.AllowCredentials = allowCredentials
+    if (cors.AllowOrigin == "") { cors.AllowOrigin = "*" }
+    if (cors.AllowMethods == "") { cors.AllowMethods = "GET,PUT,POST,DELETE,HEAD,OPTIONS" }
+    if (cors.AllowHeaders == "") { cors.AllowHeaders = "Authorization,Content-Type" }
+    return cors
+}
+
+// buildCorsAwareSwagger synthesizes a minimal single-path swagger fragment
+// for `api`, carrying its normal operation plus an OPTIONS preflight
+// operation that carries the configured CORS policy.
+func buildCorsAwareSwagger(api *whisk.Api) (string, error) {
+    swagger2 := make(map[string]interface{})
+    swagger2["swagger"] = "2.0"
+    swagger2["info"] = map[string]interface{}{"title": api.GatewayBasePath, "version": "1.0.0"}
+    swagger2["basePath"] = api.GatewayBasePath
+
+    operation := map[string]interface{}{
+        "x-ibm-op-ext": map[string]interface{}{
+            "actionName":      api.Action.Name,
+            "actionNamespace": api.Action.Namespace,
+            "backendMethod":   api.Action.BackendMethod,
+            "responseType":    api.Action.ResponseType,
+        },
+        "responses": map[string]interface{}{"default": map[string]interface{}{"description": "Default response"}},
+    }
+    if params := pathParameterSwaggerEntries(api); len(params) > 0 {
+        operation["parameters"] = params
+    }
+
+    verbs := map[string]interface{}{strings.ToLower(api.GatewayMethod): operation}
+    verbs["options"] = corsPreflightOperation(api.Cors)
+    swagger2["paths"] = map[string]interface{}{api.GatewayRelPath: verbs}
+
+    encoded, err := json.Marshal(swagger2)
+    if err != nil {
+        return "", err
+    }
+    return string(encoded), nil
+}
+
+// corsPreflightOperation builds the OPTIONS operation the gateway runs for a
+// CORS preflight request: it carries the configured allow-list as the
+// x-ibm-configuration/x-openwhisk-cors policy extensions, and declares the
+// Access-Control-Allow-* response headers. The document sets no value for
+// those headers and does not tell the gateway to echo the browser's
+// Access-Control-Request-Headers back in Access-Control-Allow-Headers.
+func corsPreflightOperation(cors *whisk.ApiCorsOptions) map[string]interface{} {
+    return map[string]interface{}{
+        "x-ibm-configuration": map[string]interface{}{
+            "cors": map[string]interface{}{
+                "enabled":          cors.Enabled,
+                "allowOrigin":      cors.AllowOrigin,
+                "allowMethods":     cors.AllowMethods,
+                "allowHeaders":     cors.AllowHeaders,
+                "maxAge":           cors.MaxAge,
+                "allowCredentials": cors.AllowCredentials,
+            },
+        },
+        "x-openwhisk-cors": map[string]interface{}{
+            "enabled": cors.Enabled,
+        },
+        "responses": map[string]interface{}{
+            "200": map[string]interface{}{
+                "description": "CORS preflight response",
+                "headers": map[string]interface{}{
+                    "Access-Control-Allow-Origin":  map[string]interface{}{"type": "string"},
+                    "Access-Control-Allow-Methods": map[string]interface{}{"type": "string"},
+                    // Declared only; the allow-list itself is the allowHeaders
+                    // entry of x-ibm-configuration.cors above.
+                    "Access-Control-Allow-Headers": map[string]interface{}{"type": "string"},
+                },
+            },
+        },
+    }
+}
+
+// getApiSwagger fetches the swagger document currently registered for
+// basePath, for use by apiUpdateCmd's diff/merge logic. It returns (nil, nil)
+// when the base path doesn't exist yet (an update that is really a create),
+// rather than treating that as an error.
+func getApiSwagger(basePath string) (*whisk.ApiSwagger, error) {
+    api := new(whisk.Api)
+    api.GatewayBasePath = basePath
+    api.Namespace = client.Config.Namespace
+    api.Id = "API:" + api.Namespace + ":" + basePath
+
+    options := new(whisk.ApiListOptions)
+    options.ApiBasePath = basePath
+
+    retApi, _, err := client.Apis.Get(api, options)
+    if err != nil {
+        return nil, err
+    }
+
+    if (retApi.Response != nil && retApi.Response.ResultArray != nil &&
+        retApi.Response.ResultArray.Apis != nil && len(retApi.Response.ResultArray.Apis) > 0) {
+        return retApi.Response.ResultArray.Apis[0].ApiValue.Swagger, nil
+    }
+    return nil, nil
+}
+
+// existingOperation looks up the swagger operation object already registered
+// for (relpath, verb) within existing, or nil if there isn't one.
+func existingOperation(existing *whisk.ApiSwagger, relpath string, verb string) map[string]interface{} {
+    if (existing == nil || existing.Paths == nil) {
+        return nil
+    }
+    ops, ok := existing.Paths[relpath]
+    if (!ok) {
+        return nil
+    }
+    return ops[strings.ToLower(verb)]
+}
+
+// printApiUpdatePatch prints the before/after operation for `wsk api update
+// --dry-run`, so a user can see exactly what would change before applying it.
+func printApiUpdatePatch(relpath string, verb string, before map[string]interface{}, after map[string]interface{}) {
+    patch := map[string]interface{}{
+        "path":   relpath,
+        "verb":   strings.ToLower(verb),
+        "before": before,
+        "after":  after,
+    }
+    printJSON(patch)
+}
+
+// mergeApiUpdate folds api's (path, verb) operation into the existing swagger
+// document for its base path, leaving every other path/verb untouched. This
+// is what lets `wsk api update` change a single route without the delete+
+// recreate that used to take the whole base path offline in the meantime.
+func mergeApiUpdate(existing *whisk.ApiSwagger, api *whisk.Api) (string, error) {
+    swagger2 := make(map[string]interface{})
+
+    if (existing != nil) {
+        encoded, err := json.Marshal(existing)
+        if err != nil {
+            return "", err
+        }
+        if err := json.Unmarshal(encoded, &swagger2); err != nil {
+            return "", err
+        }
+    } else {
+        swagger2["swagger"] = "2.0"
+        swagger2["info"] = map[string]interface{}{"title": api.GatewayBasePath, "version": "1.0.0"}
+        swagger2["basePath"] = api.GatewayBasePath
+    }
+
+    paths, ok := swagger2["paths"].(map[string]interface{})
+    if (!ok) {
+        paths = make(map[string]interface{})
+    }
+    verbs, ok := paths[api.GatewayRelPath].(map[string]interface{})
+    if (!ok) {
+        verbs = make(map[string]interface{})
+    }
+
+    operation := map[string]interface{}{
+        "x-ibm-op-ext": map[string]interface{}{
+            "actionName":      api.Action.Name,
+            "actionNamespace": api.Action.Namespace,
+            "backendMethod":   api.Action.BackendMethod,
+            "responseType":    api.Action.ResponseType,
+        },
+        "responses": map[string]interface{}{"default": map[string]interface{}{"description": "Default response"}},
+    }
+    if params := pathParameterSwaggerEntries(api); len(params) > 0 {
+        operation["parameters"] = params
+    }
+    verbs[strings.ToLower(api.GatewayMethod)] = operation
+    if (api.Cors != nil) {
+        verbs["options"] = corsPreflightOperation(api.Cors)
+    }
+
+    paths[api.GatewayRelPath] = verbs
+    swagger2["paths"] = paths
+
+    encoded, err := json.MarshalIndent(swagger2, "", "  ")
+    if err != nil {
+        return "", err
+    }
+    return string(encoded), nil
+}
+
+// translateOpenApi3ToSwagger2 converts an OpenAPI 3.x document into the Swagger 2.0
+// shape parseSwaggerApi() expects, downgrading `requestBody` and
+// `components.securitySchemes` to their Swagger 2 equivalents (a `body` parameter
+// and `securityDefinitions`, respectively).
+func translateOpenApi3ToSwagger2(openapiDoc string) (string, error) {
+    doc := make(map[string]interface{})
+    if err := json.Unmarshal([]byte(openapiDoc), &doc); err != nil {
+        return "", err
+    }
+
+    swagger2 := make(map[string]interface{})
+    swagger2["swagger"] = "2.0"
+    swagger2["info"] = doc["info"]
+    swagger2["basePath"] = "/"
+
+    // The basePath/host/schemes triple Swagger 2.0 expects as separate fields is
+    // derived from the first entry in OpenAPI 3's `servers` array.
+    if servers, ok := doc["servers"].([]interface{}); ok && len(servers) > 0 {
+        if server, ok := servers[0].(map[string]interface{}); ok {
+            if rawUrl, ok := server["url"].(string); ok {
+                scheme, host, basePath := splitServerUrl(rawUrl)
+                swagger2["schemes"] = []string{scheme}
+                swagger2["host"] = host
+                swagger2["basePath"] = basePath
+            }
+        }
+    }
+
+    paths := make(map[string]interface{})
+    if rawPaths, ok := doc["paths"].(map[string]interface{}); ok {
+        for path, rawOps := range rawPaths {
+            ops, ok := rawOps.(map[string]interface{})
+            if !ok {
+                continue
+            }
+            verbs := make(map[string]interface{})
+            for verb, rawOp := range ops {
+                op, ok := rawOp.(map[string]interface{})
+                if !ok {
+                    continue
+                }
+                // OpenAPI 3 moved the request payload out of `parameters` and into
+                // its own `requestBody`; Swagger 2.0 only knows a `body` parameter.
+                if requestBody, ok := op["requestBody"].(map[string]interface{}); ok {
+                    delete(op, "requestBody")
+                    bodyParam := map[string]interface{}{
+                        "name":     "body",
+                        "in":       "body",
+                        "required": requestBody["required"],
+                    }
+                    if content, ok := requestBody["content"].(map[string]interface{}); ok {
+                        if jsonContent, ok := content["application/json"].(map[string]interface{}); ok {
+                            bodyParam["schema"] = jsonContent["schema"]
+                        }
+                    }
+                    existing, _ := op["parameters"].([]interface{})
+                    op["parameters"] = append(existing, bodyParam)
                 }
+                rewriteComponentSchemaRefs(op)
+                verbs[verb] = op
+            }
+            paths[path] = verbs
+        }
+    }
+    swagger2["paths"] = paths
+
+    if components, ok := doc["components"].(map[string]interface{}); ok {
+        if securitySchemes, ok := components["securitySchemes"]; ok {
+            swagger2["securityDefinitions"] = securitySchemes
+        }
+        // Swagger 2.0 keeps reusable schemas at the top level under
+        // `definitions` rather than nested under `components.schemas`.
+        if schemas, ok := components["schemas"]; ok {
+            swagger2["definitions"] = schemas
+        }
+    }
+
+    translated, err := json.Marshal(swagger2)
+    if err != nil {
+        return "", err
+    }
+    return string(translated), nil
+}
+
+// rewriteComponentSchemaRefs rewrites "$ref": "#/components/schemas/X" entries
+// (OpenAPI 3) to "#/definitions/X" (Swagger 2.0) anywhere they appear within
+// an operation object, now that components.schemas has been flattened to the
+// document's top-level `definitions`.
+func rewriteComponentSchemaRefs(node interface{}) {
+    switch value := node.(type) {
+    case map[string]interface{}:
+        if ref, ok := value["$ref"].(string); ok {
+            value["$ref"] = strings.Replace(ref, "#/components/schemas/", "#/definitions/", 1)
+        }
+        for _, child := range value {
+            rewriteComponentSchemaRefs(child)
+        }
+    case []interface{}:
+        for _, child := range value {
+            rewriteComponentSchemaRefs(child)
+        }
+    }
+}
+
+// splitServerUrl breaks an OpenAPI 3 `servers[].url` entry (e.g.
+// "https://api.example.com/v1") into the scheme/host/basePath triple that
+// Swagger 2.0 expects as separate top-level fields.
+func splitServerUrl(rawUrl string) (scheme string, host string, basePath string) {
+    scheme = "https"
+    basePath = "/"
+
+    rest := rawUrl
+    if idx := strings.Index(rest, "://"); idx != -1 {
+        scheme = rest[:idx]
+        rest = rest[idx+3:]
+    }
+    if idx := strings.Index(rest, "/"); idx != -1 {
+        host = rest[:idx]
+        basePath = rest[idx:]
+    } else {
+        host = rest
+    }
+    return scheme, host, basePath
+}
+
+// apiExportPageSize is the page size listAllApisForExport() requests on each
+// call to client.Apis.List. It is independent of flags.common.limit/skip
+// (apiExportCmd doesn't register those flags) because a whole-namespace
+// export needs every API regardless of the controller's default page size,
+// not just the first page.
+const apiExportPageSize = 200
+
+// listAllApisForExport pages through client.Apis.List until it is exhausted,
+// so `wsk api export` (no BASE_PATH) covers every API in the namespace
+// instead of silently stopping at the first page.
+func listAllApisForExport() ([]*whisk.RetApi, error) {
+    var apis []*whisk.RetApi
+    skip := 0
+
+    for {
+        options := new(whisk.ApiListOptions)
+        options.Limit = apiExportPageSize
+        options.Skip = skip
+
+        retApiArray, _, err := client.Apis.List(options)
+        if err != nil {
+            return nil, err
+        }
+
+        var page []*whisk.RetApi
+        if (retApiArray.Response != nil && retApiArray.Response.ResultArray != nil) {
+            for _, entry := range retApiArray.Response.ResultArray.Apis {
+                page = append(page, entry.ApiValue)
+            }
+        }
+        apis = append(apis, page...)
+
+        if (len(page) < apiExportPageSize) {
+            break
+        }
+        skip += apiExportPageSize
+    }
+
+    return apis, nil
+}
+
+// aggregateApiSwaggers merges the swagger documents of multiple APIs into a
+// single document, for `wsk api export` with no BASE_PATH argument (exporting
+// every API in the namespace in one go). The result's info/host/schemes are
+// copied from the first API with a swagger document; only basePath/Paths are
+// actually aggregated.
+//
+// When every API shares the same base path, Paths is merged as-is (each
+// path is already relative to that shared basePath, the same convention used
+// everywhere else in this file). Otherwise there is no single basePath the
+// merged document could declare, so each API's own basePath is folded into
+// its path keys instead and the aggregate is exported relative to "/" -
+// without this, a document combining e.g. "/a" and "/b" would declare one
+// top-level basePath (say "/a") while also keying paths as "/a/x" and "/b/y",
+// which re-imports as "/a/a/x" and "/a/b/y".
+//
+// Because Paths and its nested verb maps are plain Go maps, json/yaml
+// encoding of the result sorts keys alphabetically, giving a deterministic,
+// diff-friendly export regardless of the order apis was returned in.
+func aggregateApiSwaggers(apis []*whisk.RetApi) (*whisk.ApiSwagger, error) {
+    aggregate := new(whisk.ApiSwagger)
+    haveBase := false
+    commonBasePath := ""
+    mixedBasePaths := false
+
+    for _, api := range apis {
+        if (api == nil || api.Swagger == nil) {
+            continue
+        }
+        if (!haveBase) {
+            encoded, err := json.Marshal(api.Swagger)
+            if err != nil {
+                return nil, err
+            }
+            if err := json.Unmarshal(encoded, aggregate); err != nil {
+                return nil, err
+            }
+            haveBase = true
+            commonBasePath = api.Swagger.BasePath
+        } else if (api.Swagger.BasePath != commonBasePath) {
+            mixedBasePaths = true
+        }
+    }
+
+    paths := make(map[string]map[string]map[string]interface{})
+    for _, api := range apis {
+        if (api == nil || api.Swagger == nil) {
+            continue
+        }
+        for path, ops := range api.Swagger.Paths {
+            key := path
+            if (mixedBasePaths) {
+                key = gatewayUrl(api.Swagger.BasePath, path)
             }
+            paths[key] = ops
+        }
+    }
+    if (mixedBasePaths) {
+        aggregate.BasePath = "/"
+    }
+
+    aggregate.Paths = paths
+    return aggregate, nil
+}
+
+// stripOpenWhiskExtensions removes the "x-ibm-op-ext" vendor extension from
+// every operation in a swagger document, leaving a plain document that
+// round-trips cleanly through parseSwaggerApi() on re-import elsewhere.
+func stripOpenWhiskExtensions(swagger *whisk.ApiSwagger) {
+    for path, _ := range swagger.Paths {
+        for op, _ := range swagger.Paths[path] {
+            delete(swagger.Paths[path][op], "x-ibm-op-ext")
+        }
+    }
+}
+
+// formatApiExport renders a swagger document in the format requested by
+// `wsk api export --format`.  "swagger2"/"json" (the default) emits the
+// document as-is; "openapi3" downgrades-in-reverse to an OpenAPI 3 document
+// via swagger2ToOpenApi3. "yaml" is intentionally not offered here: nothing
+// behind `--config-file` parses YAML swagger/OpenAPI documents (only the
+// unrelated `wsk api sync` manifest does), so a YAML export could not be
+// re-imported and would break the round-trip this command exists for.
+func formatApiExport(swagger *whisk.ApiSwagger, format string) (string, error) {
+    switch strings.ToLower(format) {
+    case "", "swagger2", "json":
+        encoded, err := json.MarshalIndent(swagger, "", "  ")
+        if err != nil {
+            return "", err
         }
+        return string(encoded), nil
+    case "openapi3":
+        return swagger2ToOpenApi3(swagger)
+    default:
+        return "", fmt.Errorf("unknown format '%s'; valid values are swagger2, openapi3, json", format)
     }
-    // Remove possible duplicate path delimiter that can occur when the basepath ends with '/'
-    return strings.Replace(url, "//", "/", -1)
+}
+
+// swagger2ToOpenApi3 is the inverse of translateOpenApi3ToSwagger2: it emits a
+// minimal OpenAPI 3 document (servers/paths) from a Swagger 2.0 document,
+// sufficient to re-import via `wsk api create --config-file`.
+func swagger2ToOpenApi3(swagger *whisk.ApiSwagger) (string, error) {
+    doc := make(map[string]interface{})
+    doc["openapi"] = "3.0.0"
+    doc["info"] = swagger.Info
+
+    scheme := "https"
+    if (len(swagger.Schemes) > 0) {
+        scheme = swagger.Schemes[0]
+    }
+    doc["servers"] = []map[string]interface{}{
+        {"url": scheme + "://" + swagger.Host + swagger.BasePath},
+    }
+
+    paths := make(map[string]interface{})
+    for path, ops := range swagger.Paths {
+        verbs := make(map[string]interface{})
+        for op, opv := range ops {
+            verbs[op] = opv
+        }
+        paths[path] = verbs
+    }
+    doc["paths"] = paths
+
+    encoded, err := json.MarshalIndent(doc, "", "  ")
+    if err != nil {
+        return "", err
+    }
+    return string(encoded), nil
 }
 
 ///////////
@@ -720,21 +2269,58 @@ func getManagedUrl(api *whisk.RetApi, relpath string, operation string) (url str
 func init() {
     apiCreateCmd.Flags().StringVarP(&flags.api.apiname, "apiname", "n", "", wski18n.T("Friendly name of the API; ignored when CFG_FILE is specified (default BASE_PATH)"))
     apiCreateCmd.Flags().StringVarP(&flags.api.configfile, "config-file", "c", "", wski18n.T("`CFG_FILE` containing API configuration in swagger JSON format"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.configdir, "config-dir", "", "", wski18n.T("`CFG_DIR` containing one swagger/OpenAPI fragment per file; creates all of them in one batch"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.responsetype, "response-type", "", "json", wski18n.T("Set the web action response `TYPE`. Valid values are json, http, text, html, svg"))
+    apiCreateCmd.Flags().BoolVarP(&flags.api.cors, "cors", "", false, wski18n.T("enable CORS for this route"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.corsAllowOrigin, "cors-allow-origin", "", "", wski18n.T("`ORIGIN` to return in Access-Control-Allow-Origin (default *)"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.corsAllowMethods, "cors-allow-methods", "", "", wski18n.T("comma separated `METHODS` to return in Access-Control-Allow-Methods"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.corsAllowHeaders, "cors-allow-headers", "", "", wski18n.T("comma separated `HEADERS` to return in Access-Control-Allow-Headers"))
+    apiCreateCmd.Flags().IntVarP(&flags.api.corsMaxAge, "cors-max-age", "", 0, wski18n.T("`SECONDS` browsers may cache the preflight response for"))
+    apiCreateCmd.Flags().BoolVarP(&flags.api.corsAllowCredentials, "cors-allow-credentials", "", false, wski18n.T("return Access-Control-Allow-Credentials: true"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.pathparameters, "path-parameters", "", "", wski18n.T("comma separated `NAME:TYPE` pairs declaring the {name} segments in API_PATH (type is string, integer, or number; default string)"))
+    apiCreateCmd.Flags().BoolVarP(&flags.api.pathparametersnopassthrough, "path-parameters-no-passthrough", "", false, wski18n.T("do not pass path parameters through to the action as top-level input keys"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.ratelimit, "rate-limit", "", "", wski18n.T("limit requests to `N/(sec|min|hour)`, e.g. \"100/min\""))
+    apiCreateCmd.Flags().BoolVarP(&flags.api.requireapikey, "require-api-key", "", false, wski18n.T("require an API key to invoke this route"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.jwtIssuer, "jwt-issuer", "", "", wski18n.T("require a JWT from `ISSUER` (must be paired with --jwt-audience)"))
+    apiCreateCmd.Flags().StringVarP(&flags.api.jwtAudience, "jwt-audience", "", "", wski18n.T("require a JWT for `AUDIENCE` (must be paired with --jwt-issuer)"))
 
     //apiUpdateCmd.Flags().StringVarP(&flags.api.action, "action", "a", "", wski18n.T("`ACTION` to invoke when API is called"))
     //apiUpdateCmd.Flags().StringVarP(&flags.api.path, "path", "p", "", wski18n.T("relative `PATH` of API"))
     //apiUpdateCmd.Flags().StringVarP(&flags.api.verb, "method", "m", "", wski18n.T("API `VERB`"))
+    apiUpdateCmd.Flags().StringVarP(&flags.api.responsetype, "response-type", "", "json", wski18n.T("Set the web action response `TYPE`. Valid values are json, http, text, html, svg"))
+    apiUpdateCmd.Flags().BoolVarP(&flags.api.cors, "cors", "", false, wski18n.T("enable CORS for this route"))
+    apiUpdateCmd.Flags().StringVarP(&flags.api.corsAllowOrigin, "cors-allow-origin", "", "", wski18n.T("`ORIGIN` to return in Access-Control-Allow-Origin (default *)"))
+    apiUpdateCmd.Flags().StringVarP(&flags.api.corsAllowMethods, "cors-allow-methods", "", "", wski18n.T("comma separated `METHODS` to return in Access-Control-Allow-Methods"))
+    apiUpdateCmd.Flags().StringVarP(&flags.api.corsAllowHeaders, "cors-allow-headers", "", "", wski18n.T("comma separated `HEADERS` to return in Access-Control-Allow-Headers"))
+    apiUpdateCmd.Flags().IntVarP(&flags.api.corsMaxAge, "cors-max-age", "", 0, wski18n.T("`SECONDS` browsers may cache the preflight response for"))
+    apiUpdateCmd.Flags().BoolVarP(&flags.api.corsAllowCredentials, "cors-allow-credentials", "", false, wski18n.T("return Access-Control-Allow-Credentials: true"))
+    apiUpdateCmd.Flags().StringVarP(&flags.api.pathparameters, "path-parameters", "", "", wski18n.T("comma separated `NAME:TYPE` pairs declaring the {name} segments in API_PATH (type is string, integer, or number; default string)"))
+    apiUpdateCmd.Flags().BoolVarP(&flags.api.pathparametersnopassthrough, "path-parameters-no-passthrough", "", false, wski18n.T("do not pass path parameters through to the action as top-level input keys"))
+    apiUpdateCmd.Flags().BoolVarP(&flags.api.dryrun, "dry-run", "", false, wski18n.T("print the operation patch that would be applied without applying it"))
+    apiUpdateCmd.Flags().BoolVarP(&flags.api.force, "force", "", false, wski18n.T("replace the entire base path instead of merging in just this route"))
 
     apiGetCmd.Flags().BoolVarP(&flags.common.detail, "full", "f", false, wski18n.T("display full API configuration details"))
+    apiGetCmd.Flags().StringVarP(&flags.api.query, "query", "", "", wski18n.T("a JMESPath-style `EXPR` selecting a subtree of the result (e.g. \"paths\")"))
+    apiGetCmd.Flags().StringVarP(&flags.api.outputformat, "output", "o", "", wski18n.T("result `FORMAT`: json (default), yaml, or jsonpath=EXPR"))
 
     apiListCmd.Flags().IntVarP(&flags.common.skip, "skip", "s", 0, wski18n.T("exclude the first `SKIP` number of actions from the result"))
     apiListCmd.Flags().IntVarP(&flags.common.limit, "limit", "l", 30, wski18n.T("only return `LIMIT` number of actions from the collection"))
+    apiListCmd.Flags().StringVarP(&flags.api.query, "query", "", "", wski18n.T("a JMESPath-style `EXPR` selecting a subtree of the result, e.g. \"paths\" for a single API's swagger paths, or \"paths[]\" to project over them (run without --query and -o json first to see the full shape)"))
+    apiListCmd.Flags().StringVarP(&flags.api.outputformat, "output", "o", "table", wski18n.T("result `FORMAT`: table (default), json, yaml, or jsonpath=EXPR"))
+
+    apiSyncCmd.Flags().BoolVarP(&flags.api.dryrun, "dry-run", "", false, wski18n.T("print the sync plan without applying it"))
+
+    apiExportCmd.Flags().StringVarP(&flags.api.format, "format", "", "swagger2", wski18n.T("export `FORMAT`: swagger2, openapi3, or json"))
+    apiExportCmd.Flags().StringVarP(&flags.api.output, "output", "o", "", wski18n.T("`FILE` to write the exported API to (default stdout)"))
+    apiExportCmd.Flags().BoolVarP(&flags.api.keepextensions, "keep-extensions", "", false, wski18n.T("preserve the x-ibm-op-ext vendor extensions in the exported document"))
 
     apiCmd.AddCommand(
         apiCreateCmd,
-        //apiUpdateCmd,
+        apiUpdateCmd,
         apiGetCmd,
         apiDeleteCmd,
         apiListCmd,
+        apiSyncCmd,
+        apiExportCmd,
     )
 }
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "../../go-whisk/whisk"
+)
+
+const validFragment = `{
+  "swagger": "2.0",
+  "info": {"title": "frag", "version": "1.0.0"},
+  "basePath": "/items",
+  "paths": {"/%s": {"get": {"responses": {"default": {"description": "Default response"}}}}}
+}`
+
+func writeTestFragment(t *testing.T, dir string, name string, contents string) {
+    t.Helper()
+    if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+        t.Fatalf("ioutil.WriteFile(%s) error: %s", name, err)
+    }
+}
+
+// TestParseSwaggerApiDirReportsPartialFailures checks that one malformed
+// fragment doesn't abort the whole --config-dir batch; the good fragments are
+// still parsed and the bad one is reported back as a failure.
+func TestParseSwaggerApiDirReportsPartialFailures(t *testing.T) {
+    dir, err := ioutil.TempDir("", "api-config-dir-test")
+    if err != nil {
+        t.Fatalf("ioutil.TempDir() error: %s", err)
+    }
+    defer os.RemoveAll(dir)
+
+    writeTestFragment(t, dir, "good.json", sprintfFragment("one"))
+    writeTestFragment(t, dir, "bad.json", "not valid json")
+
+    apis, err := parseSwaggerApiDir(dir)
+    if err != nil {
+        t.Fatalf("parseSwaggerApiDir() unexpected error: %s", err)
+    }
+    if len(apis) != 1 {
+        t.Fatalf("len(apis) = %d, want 1 (the malformed fragment should be skipped, not fatal)", len(apis))
+    }
+}
+
+// TestParseSwaggerApiDirAllFragmentsFail checks that a directory where every
+// fragment fails to parse is reported as an error rather than silently
+// returning zero APIs.
+func TestParseSwaggerApiDirAllFragmentsFail(t *testing.T) {
+    dir, err := ioutil.TempDir("", "api-config-dir-test")
+    if err != nil {
+        t.Fatalf("ioutil.TempDir() error: %s", err)
+    }
+    defer os.RemoveAll(dir)
+
+    writeTestFragment(t, dir, "bad.json", "not valid json")
+
+    if _, err := parseSwaggerApiDir(dir); err == nil {
+        t.Fatal("expected an error when no fragment in the directory could be parsed")
+    }
+}
+
+// TestCheckDuplicateRoutesDetectsCrossFileCollision checks that two fragments
+// declaring the same (path, verb) are flagged, naming the file that declared
+// it first.
+func TestCheckDuplicateRoutesDetectsCrossFileCollision(t *testing.T) {
+    seenRoutes := make(map[string]string)
+
+    first := &whisk.Api{Swagger: sprintfFragment("one")}
+    if err := checkDuplicateRoutes(first, "first.json", seenRoutes); err != nil {
+        t.Fatalf("checkDuplicateRoutes(first) unexpected error: %s", err)
+    }
+
+    second := &whisk.Api{Swagger: sprintfFragment("one")}
+    err := checkDuplicateRoutes(second, "second.json", seenRoutes)
+    if err == nil {
+        t.Fatal("expected a duplicate-route error for the second fragment")
+    }
+    if !strings.Contains(err.Error(), "first.json") {
+        t.Errorf("expected the error to name the file that declared the route first, got: %s", err)
+    }
+}
+
+func sprintfFragment(segment string) string {
+    return strings.Replace(validFragment, "%s", segment, 1)
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "strings"
+    "testing"
+)
+
+// TestParseApiCorsAndPathParametersTogether is a regression test for a bug
+// where api.PathParameters was populated *after* the --cors block already
+// called buildCorsAwareSwagger(api), so a route combining --cors and
+// --path-parameters silently got no "parameters" swagger entry at all.
+func TestParseApiCorsAndPathParametersTogether(t *testing.T) {
+    savedApiFlags := flags.api
+    defer func() { flags.api = savedApiFlags }()
+
+    flags.api.cors = true
+    flags.api.pathparameters = "id:integer"
+    flags.api.pathparametersnopassthrough = false
+    flags.api.jwtIssuer = ""
+    flags.api.jwtAudience = ""
+    flags.api.ratelimit = ""
+    flags.api.requireapikey = false
+    flags.api.responsetype = ""
+    flags.api.apiname = ""
+
+    api, err := parseApi(nil, []string{"/items/{id}", "get", "ns/pkg/action"})
+    if err != nil {
+        t.Fatalf("parseApi() error: %s", err)
+    }
+
+    if len(api.PathParameters) != 1 || api.PathParameters[0].Name != "id" || api.PathParameters[0].Type != "integer" {
+        t.Fatalf("expected a single 'id:integer' path parameter, got %#v", api.PathParameters)
+    }
+
+    if !strings.Contains(api.Swagger, `"parameters"`) {
+        t.Fatalf("CORS-enabled swagger is missing the path-parameter entry: %s", api.Swagger)
+    }
+    if !strings.Contains(api.Swagger, `"id"`) {
+        t.Fatalf("CORS-enabled swagger is missing the 'id' path parameter name: %s", api.Swagger)
+    }
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "encoding/json"
+    "testing"
+
+    "../../go-whisk/whisk"
+)
+
+func retApiWithSwagger(basePath string, relPath string) *whisk.RetApi {
+    return &whisk.RetApi{
+        Swagger: &whisk.ApiSwagger{
+            SwaggerName: "2.0",
+            Info:        map[string]interface{}{"title": basePath, "version": "1.0.0"},
+            BasePath:    basePath,
+            Paths: map[string]map[string]map[string]interface{}{
+                relPath: {
+                    "get": {"responses": map[string]interface{}{"default": map[string]interface{}{"description": "Default response"}}},
+                },
+            },
+        },
+    }
+}
+
+// TestAggregateApiSwaggersSameBasePath checks the common case - every API
+// sharing one base path - keeps paths relative, matching the convention used
+// everywhere else in this file.
+func TestAggregateApiSwaggersSameBasePath(t *testing.T) {
+    apis := []*whisk.RetApi{
+        retApiWithSwagger("/items", "/x"),
+        retApiWithSwagger("/items", "/y"),
+    }
+
+    aggregate, err := aggregateApiSwaggers(apis)
+    if err != nil {
+        t.Fatalf("aggregateApiSwaggers() error: %s", err)
+    }
+    if aggregate.BasePath != "/items" {
+        t.Errorf("BasePath = %s, want /items", aggregate.BasePath)
+    }
+    if _, ok := aggregate.Paths["/x"]; !ok {
+        t.Error("expected /x to be keyed relative to the shared basePath")
+    }
+    if _, ok := aggregate.Paths["/y"]; !ok {
+        t.Error("expected /y to be keyed relative to the shared basePath")
+    }
+}
+
+// TestAggregateApiSwaggersMixedBasePaths is the whole-namespace case this
+// function exists for: APIs registered under different base paths must not
+// be merged under one of their base paths, or the result double-prefixes on
+// re-import (basePath "/a" + path "/a/x" -> "/a/a/x").
+func TestAggregateApiSwaggersMixedBasePaths(t *testing.T) {
+    apis := []*whisk.RetApi{
+        retApiWithSwagger("/a", "/x"),
+        retApiWithSwagger("/b", "/y"),
+    }
+
+    aggregate, err := aggregateApiSwaggers(apis)
+    if err != nil {
+        t.Fatalf("aggregateApiSwaggers() error: %s", err)
+    }
+    if aggregate.BasePath != "/" {
+        t.Errorf("BasePath = %s, want / (root) when base paths differ", aggregate.BasePath)
+    }
+    if _, ok := aggregate.Paths["/a/x"]; !ok {
+        t.Errorf("expected /a/x in aggregated paths, got %#v", aggregate.Paths)
+    }
+    if _, ok := aggregate.Paths["/b/y"]; !ok {
+        t.Errorf("expected /b/y in aggregated paths, got %#v", aggregate.Paths)
+    }
+    // Neither API's own basePath should be re-applied on top of the joined path.
+    if _, ok := aggregate.Paths["/a/a/x"]; ok {
+        t.Error("path was double-prefixed with its own basePath")
+    }
+}
+
+// TestFormatApiExportRoundTripsThroughSwaggerObj checks that the default
+// export format produces a document that parses back into the same
+// whisk.ApiSwagger shape create/get already use, i.e. the create -> export ->
+// create round trip this command exists for.
+func TestFormatApiExportRoundTripsThroughSwaggerObj(t *testing.T) {
+    apis := []*whisk.RetApi{retApiWithSwagger("/items", "/x")}
+    aggregate, err := aggregateApiSwaggers(apis)
+    if err != nil {
+        t.Fatalf("aggregateApiSwaggers() error: %s", err)
+    }
+
+    document, err := formatApiExport(aggregate, "swagger2")
+    if err != nil {
+        t.Fatalf("formatApiExport() error: %s", err)
+    }
+
+    reparsed := new(whisk.ApiSwagger)
+    if err := json.Unmarshal([]byte(document), reparsed); err != nil {
+        t.Fatalf("exported document does not parse back into whisk.ApiSwagger: %s", err)
+    }
+    if reparsed.BasePath != "/items" {
+        t.Errorf("reparsed BasePath = %s, want /items", reparsed.BasePath)
+    }
+    if _, ok := reparsed.Paths["/x"]; !ok {
+        t.Errorf("reparsed document missing /x, got %#v", reparsed.Paths)
+    }
+}
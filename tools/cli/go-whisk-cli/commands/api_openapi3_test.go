@@ -0,0 +1,120 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "encoding/json"
+    "strings"
+    "testing"
+)
+
+const sampleOpenApi3Doc = `{
+  "openapi": "3.0.1",
+  "info": {"title": "pets", "version": "1.0.0"},
+  "servers": [{"url": "https://example.com/v1"}],
+  "paths": {
+    "/pets": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}
+          }
+        },
+        "responses": {"default": {"description": "Default response"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {"type": "object"}
+    }
+  }
+}`
+
+func TestValidateOpenApi3Document(t *testing.T) {
+    doc := new(apiSpecVersion)
+    if err := json.Unmarshal([]byte(sampleOpenApi3Doc), doc); err != nil {
+        t.Fatalf("json.Unmarshal() error: %s", err)
+    }
+    if err := validateOpenApi3Document(doc); err != nil {
+        t.Fatalf("validateOpenApi3Document() unexpected error: %s", err)
+    }
+}
+
+func TestValidateOpenApi3DocumentRejectsUnsupportedVersion(t *testing.T) {
+    doc := &apiSpecVersion{OpenApi: "2.0", Info: map[string]interface{}{"title": "x"}}
+    err := validateOpenApi3Document(doc)
+    if err == nil {
+        t.Fatal("expected an error for an unsupported openapi version")
+    }
+    if !strings.Contains(err.Error(), "unsupported openapi version") {
+        t.Fatalf("expected the error to name the bad version, got: %s", err)
+    }
+}
+
+func TestValidateOpenApi3DocumentRejectsMissingPaths(t *testing.T) {
+    doc := &apiSpecVersion{OpenApi: "3.0.0", Info: map[string]interface{}{"title": "x"}}
+    err := validateOpenApi3Document(doc)
+    if err == nil {
+        t.Fatal("expected an error when neither paths nor servers[0].url are present")
+    }
+}
+
+// TestTranslateOpenApi3ToSwagger2RoundTrips verifies the translated document
+// is valid Swagger 2.0 shaped the way parseSwaggerApiFile()'s downstream
+// whisk.ApiSwagger parse expects: basePath/host/schemes split out of
+// servers[0].url, requestBody folded into a body parameter, and
+// components.schemas refs rewritten to definitions.
+func TestTranslateOpenApi3ToSwagger2RoundTrips(t *testing.T) {
+    translated, err := translateOpenApi3ToSwagger2(sampleOpenApi3Doc)
+    if err != nil {
+        t.Fatalf("translateOpenApi3ToSwagger2() error: %s", err)
+    }
+
+    var swagger2 map[string]interface{}
+    if err := json.Unmarshal([]byte(translated), &swagger2); err != nil {
+        t.Fatalf("translated document is not valid JSON: %s", err)
+    }
+
+    if swagger2["basePath"] != "/v1" {
+        t.Errorf("basePath = %v, want /v1", swagger2["basePath"])
+    }
+    if swagger2["host"] != "example.com" {
+        t.Errorf("host = %v, want example.com", swagger2["host"])
+    }
+
+    paths := swagger2["paths"].(map[string]interface{})
+    post := paths["/pets"].(map[string]interface{})["post"].(map[string]interface{})
+    if _, ok := post["requestBody"]; ok {
+        t.Error("requestBody should have been removed from the translated operation")
+    }
+    params := post["parameters"].([]interface{})
+    bodyParam := params[0].(map[string]interface{})
+    if bodyParam["in"] != "body" {
+        t.Errorf("body parameter in = %v, want body", bodyParam["in"])
+    }
+    schema := bodyParam["schema"].(map[string]interface{})
+    if schema["$ref"] != "#/definitions/Pet" {
+        t.Errorf("schema $ref = %v, want #/definitions/Pet", schema["$ref"])
+    }
+
+    definitions := swagger2["definitions"].(map[string]interface{})
+    if _, ok := definitions["Pet"]; !ok {
+        t.Error("components.schemas.Pet should have been flattened into definitions.Pet")
+    }
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "testing"
+)
+
+func TestIsQueryOutputFormat(t *testing.T) {
+    cases := map[string]bool{
+        "":       false,
+        "table":  false,
+        "json":   true,
+        "yaml":   true,
+        "jsonpath=paths": true,
+    }
+    for format, want := range cases {
+        if got := isQueryOutputFormat(format); got != want {
+            t.Errorf("isQueryOutputFormat(%q) = %v, want %v", format, got, want)
+        }
+    }
+}
+
+func TestToGenericJSONRoundTripsStruct(t *testing.T) {
+    type inner struct {
+        Name string `json:"name"`
+    }
+    type outer struct {
+        Inner inner `json:"inner"`
+    }
+
+    generic, err := toGenericJSON(outer{Inner: inner{Name: "x"}})
+    if err != nil {
+        t.Fatalf("toGenericJSON() error: %s", err)
+    }
+
+    m, ok := generic.(map[string]interface{})
+    if !ok {
+        t.Fatalf("generic = %#v, want a map[string]interface{}", generic)
+    }
+    innerMap, ok := m["inner"].(map[string]interface{})
+    if !ok {
+        t.Fatalf("m[\"inner\"] = %#v, want a map[string]interface{}", m["inner"])
+    }
+    if innerMap["name"] != "x" {
+        t.Errorf("m[\"inner\"][\"name\"] = %#v, want \"x\"", innerMap["name"])
+    }
+}
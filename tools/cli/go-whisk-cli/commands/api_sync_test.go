@@ -0,0 +1,150 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "testing"
+)
+
+func existingOp(actionName string, actionNamespace string, responseType string) map[string]interface{} {
+    return map[string]interface{}{
+        "x-ibm-op-ext": map[string]interface{}{
+            "actionName":      actionName,
+            "actionNamespace": actionNamespace,
+            "responseType":    responseType,
+        },
+    }
+}
+
+func TestApiSyncRouteUpToDateMatches(t *testing.T) {
+    route := apiManifestRoute{Action: "ns/pkg/action", ResponseType: "json", Cors: false}
+    if !apiSyncRouteUpToDate(route, existingOp("action", "ns/pkg", "json"), false) {
+        t.Error("expected an identical route to be reported up to date")
+    }
+}
+
+func TestApiSyncRouteUpToDateDefaultsResponseTypeToJson(t *testing.T) {
+    route := apiManifestRoute{Action: "ns/pkg/action", ResponseType: "", Cors: false}
+    if !apiSyncRouteUpToDate(route, existingOp("action", "ns/pkg", "json"), false) {
+        t.Error("expected an empty manifest responseType to match an existing \"json\" responseType")
+    }
+}
+
+func TestApiSyncRouteUpToDateNilExistingOp(t *testing.T) {
+    route := apiManifestRoute{Action: "ns/pkg/action"}
+    if apiSyncRouteUpToDate(route, nil, false) {
+        t.Error("expected a nil existingOp (route not yet registered) to never be up to date")
+    }
+}
+
+func TestApiSyncRouteUpToDateMismatches(t *testing.T) {
+    base := apiManifestRoute{Action: "ns/pkg/action", ResponseType: "json", Cors: true}
+    cases := map[string]map[string]interface{}{
+        "different action name":      existingOp("other", "ns/pkg", "json"),
+        "different action namespace": existingOp("action", "ns/other", "json"),
+        "different response type":    existingOp("action", "ns/pkg", "http"),
+    }
+    for name, op := range cases {
+        if apiSyncRouteUpToDate(base, op, true) {
+            t.Errorf("%s: expected route not to be up to date", name)
+        }
+    }
+
+    if apiSyncRouteUpToDate(base, existingOp("action", "ns/pkg", "json"), false) {
+        t.Error("cors mismatch (manifest wants cors, no OPTIONS registered): expected route not to be up to date")
+    }
+
+    if apiSyncRouteUpToDate(base, map[string]interface{}{"responses": map[string]interface{}{}}, true) {
+        t.Error("existingOp missing x-ibm-op-ext: expected route not to be up to date")
+    }
+}
+
+func TestApiSyncRouteUpToDateMalformedAction(t *testing.T) {
+    route := apiManifestRoute{Action: "not-a-qualified-name"}
+    if apiSyncRouteUpToDate(route, existingOp("action", "ns/pkg", "json"), false) {
+        t.Error("expected a malformed action string to never be up to date")
+    }
+}
+
+// TestComputeApiSyncPlanInsertsOnlyChangedRoutes covers planApiSync's main
+// purpose: a manifest route already matching what's registered shouldn't be
+// reinserted, but a new or changed one should.
+func TestComputeApiSyncPlanInsertsOnlyChangedRoutes(t *testing.T) {
+    entryPaths := map[string]map[string]apiManifestRoute{
+        "/x": {
+            "get":  {Action: "ns/pkg/action", ResponseType: "json"},
+            "post": {Action: "ns/pkg/other", ResponseType: "json"},
+        },
+    }
+    existing := map[string]map[string]bool{
+        "/x": {"GET": true},
+    }
+    existingOps := map[string]map[string]map[string]interface{}{
+        "/x": {"GET": existingOp("action", "ns/pkg", "json")},
+    }
+
+    plan := computeApiSyncPlan(entryPaths, existing, existingOps)
+
+    if len(plan.ToInsert) != 1 || plan.ToInsert[0].Verb != "POST" {
+        t.Fatalf("ToInsert = %#v, want exactly the new POST /x route", plan.ToInsert)
+    }
+    if len(plan.ToDelete) != 0 {
+        t.Fatalf("ToDelete = %#v, want none (GET /x is already up to date)", plan.ToDelete)
+    }
+}
+
+// TestComputeApiSyncPlanDeletesUndeclaredRoutes covers the other half: a
+// route registered on the controller but no longer present in the manifest
+// must be queued for deletion.
+func TestComputeApiSyncPlanDeletesUndeclaredRoutes(t *testing.T) {
+    entryPaths := map[string]map[string]apiManifestRoute{
+        "/x": {"get": {Action: "ns/pkg/action", ResponseType: "json"}},
+    }
+    existing := map[string]map[string]bool{
+        "/x": {"GET": true, "DELETE": true},
+        "/y": {"GET": true},
+    }
+    existingOps := map[string]map[string]map[string]interface{}{
+        "/x": {"GET": existingOp("action", "ns/pkg", "json")},
+    }
+
+    plan := computeApiSyncPlan(entryPaths, existing, existingOps)
+
+    want := []apiSyncRoute{{Path: "/x", Verb: "DELETE"}, {Path: "/y", Verb: "GET"}}
+    if len(plan.ToInsert) != 0 {
+        t.Fatalf("ToInsert = %#v, want none", plan.ToInsert)
+    }
+    if !sameRoutes(plan.ToDelete, want) {
+        t.Fatalf("ToDelete = %#v, want (in any order) %#v", plan.ToDelete, want)
+    }
+}
+
+func sameRoutes(got []apiSyncRoute, want []apiSyncRoute) bool {
+    if len(got) != len(want) {
+        return false
+    }
+    seen := make(map[apiSyncRoute]bool)
+    for _, route := range got {
+        seen[apiSyncRoute{Path: route.Path, Verb: route.Verb}] = true
+    }
+    for _, route := range want {
+        if !seen[route] {
+            return false
+        }
+    }
+    return true
+}
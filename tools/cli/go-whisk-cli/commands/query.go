@@ -0,0 +1,110 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// evalQuery is a small JMESPath-style expression evaluator supporting dotted
+// field access and "[]" projections (e.g. "paths[]"). A "[]" step can project
+// over a JSON array or, since a JSON object decodes to the same
+// map[string]interface{} shape an array's elements would (e.g.
+// ApiSwagger.Paths, keyed by relative path), over a JSON object's values too.
+// It operates on the generic interface{} shape produced by json.Unmarshal, so
+// any `wsk` subcommand that wants --query support can reuse it the same way.
+func evalQuery(data interface{}, expr string) (interface{}, error) {
+    if (strings.TrimSpace(expr) == "") {
+        return data, nil
+    }
+    return evalQuerySteps(data, strings.Split(expr, "."))
+}
+
+func evalQuerySteps(data interface{}, steps []string) (interface{}, error) {
+    if (len(steps) == 0) {
+        return data, nil
+    }
+    step := steps[0]
+    rest := steps[1:]
+
+    projecting := strings.HasSuffix(step, "[]")
+    field := strings.TrimSuffix(step, "[]")
+
+    value, err := evalField(data, field)
+    if err != nil {
+        return nil, err
+    }
+
+    if (!projecting) {
+        return evalQuerySteps(value, rest)
+    }
+
+    items, err := projectionItems(value, field)
+    if err != nil {
+        return nil, err
+    }
+
+    var results []interface{}
+    for _, item := range items {
+        result, err := evalQuerySteps(item, rest)
+        if err != nil {
+            return nil, err
+        }
+        if (result != nil) {
+            results = append(results, result)
+        }
+    }
+    return results, nil
+}
+
+// projectionItems returns the elements a "field[]" step iterates over. A JSON
+// array decodes as []interface{} and is returned as-is. A JSON object decodes
+// as map[string]interface{} and is projected over its values, sorted by key
+// so query output doesn't depend on Go's randomized map iteration order.
+func projectionItems(value interface{}, field string) ([]interface{}, error) {
+    switch typed := value.(type) {
+    case []interface{}:
+        return typed, nil
+    case map[string]interface{}:
+        keys := make([]string, 0, len(typed))
+        for key := range typed {
+            keys = append(keys, key)
+        }
+        sort.Strings(keys)
+
+        items := make([]interface{}, 0, len(keys))
+        for _, key := range keys {
+            items = append(items, typed[key])
+        }
+        return items, nil
+    default:
+        return nil, fmt.Errorf("'%s' is not a list or object; cannot apply []", field)
+    }
+}
+
+func evalField(data interface{}, field string) (interface{}, error) {
+    if (field == "") {
+        return data, nil
+    }
+    m, ok := data.(map[string]interface{})
+    if (!ok) {
+        return nil, fmt.Errorf("cannot select field '%s' from a non-object value", field)
+    }
+    return m[field], nil
+}
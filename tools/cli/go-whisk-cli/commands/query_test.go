@@ -0,0 +1,102 @@
+/*
+ * Copyright 2015-2016 IBM Corporation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestEvalQueryEmptyExprReturnsInput(t *testing.T) {
+    data := map[string]interface{}{"a": 1}
+    result, err := evalQuery(data, "")
+    if err != nil {
+        t.Fatalf("evalQuery() error: %s", err)
+    }
+    if !reflect.DeepEqual(result, data) {
+        t.Errorf("result = %#v, want %#v", result, data)
+    }
+}
+
+func TestEvalQueryDottedFieldAccess(t *testing.T) {
+    data := map[string]interface{}{
+        "info": map[string]interface{}{"title": "pets"},
+    }
+    result, err := evalQuery(data, "info.title")
+    if err != nil {
+        t.Fatalf("evalQuery() error: %s", err)
+    }
+    if result != "pets" {
+        t.Errorf("result = %#v, want \"pets\"", result)
+    }
+}
+
+func TestEvalQueryListProjection(t *testing.T) {
+    data := map[string]interface{}{
+        "apis": []interface{}{
+            map[string]interface{}{"name": "a"},
+            map[string]interface{}{"name": "b"},
+        },
+    }
+    result, err := evalQuery(data, "apis[].name")
+    if err != nil {
+        t.Fatalf("evalQuery() error: %s", err)
+    }
+    want := []interface{}{"a", "b"}
+    if !reflect.DeepEqual(result, want) {
+        t.Errorf("result = %#v, want %#v", result, want)
+    }
+}
+
+// TestEvalQueryObjectProjection covers the case the original evaluator got
+// wrong: ApiSwagger.Paths (and the verb map nested under each path) decodes
+// from JSON as a map, not a slice, so "[]" has to be able to project over an
+// object's values too, sorted by key for deterministic output.
+func TestEvalQueryObjectProjection(t *testing.T) {
+    data := map[string]interface{}{
+        "paths": map[string]interface{}{
+            "/b": map[string]interface{}{"summary": "second"},
+            "/a": map[string]interface{}{"summary": "first"},
+        },
+    }
+    result, err := evalQuery(data, "paths[].summary")
+    if err != nil {
+        t.Fatalf("evalQuery() error: %s", err)
+    }
+    want := []interface{}{"first", "second"}
+    if !reflect.DeepEqual(result, want) {
+        t.Errorf("result = %#v, want %#v (sorted by key)", result, want)
+    }
+}
+
+func TestEvalQueryProjectionOnScalarErrors(t *testing.T) {
+    data := map[string]interface{}{"count": 3}
+    if _, err := evalQuery(data, "count[]"); err == nil {
+        t.Fatal("expected an error projecting [] over a scalar value")
+    }
+}
+
+func TestEvalQueryUnknownFieldReturnsNil(t *testing.T) {
+    data := map[string]interface{}{"a": 1}
+    result, err := evalQuery(data, "missing")
+    if err != nil {
+        t.Fatalf("evalQuery() error: %s", err)
+    }
+    if result != nil {
+        t.Errorf("result = %#v, want nil for a missing field", result)
+    }
+}